@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// BatchOptions controls RunAll.
+type BatchOptions struct {
+	// Parallelism is the maximum number of commands running at once.
+	// 0 uses GOMAXPROCS.
+	Parallelism int
+
+	// FailFast cancels running commands and skips pending ones as soon as
+	// one command fails.
+	FailFast bool
+}
+
+// BatchError reports the failure of one command in RunAll.
+type BatchError struct {
+	Index   int // position in the specs slice
+	Command string
+	Err     error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch command %d (%s): %v", e.Index, e.Command, e.Err)
+}
+
+func (e *BatchError) Unwrap() error { return e.Err }
+
+// ErrSkipped is reported for commands that never ran because of FailFast.
+var ErrSkipped = errors.New("skipped after earlier failure")
+
+// RunAll runs specs concurrently with bounded parallelism. The results are
+// in the same order as specs and are never nil; commands skipped by FailFast
+// have ExitCode -1. The error joins one *BatchError per failed (or skipped)
+// command, or is nil if all succeeded.
+func RunAll(ctx context.Context, specs []Spec, opts BatchOptions) ([]*Result, error) {
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*Result, len(specs))
+	errs := make([]error, len(specs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+
+	for i, spec := range specs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			results[i] = &Result{ExitCode: -1}
+			errs[i] = &BatchError{Index: i, Command: spec.Command, Err: skipReason(parent, ctx)}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res, err := RunResult(ctx, spec.Command, spec.Args, spec.Opts)
+			results[i] = res
+			if err != nil {
+				errs[i] = &BatchError{Index: i, Command: spec.Command, Err: err}
+				if opts.FailFast {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// skipReason explains why a pending command was not started: the caller's
+// context ended, or FailFast cancelled the batch.
+func skipReason(parent, ctx context.Context) error {
+	if err := parent.Err(); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ErrSkipped
+	}
+	return nil
+}
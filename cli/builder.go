@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"slices"
+	"time"
+)
+
+// Builder is a fluent, immutable way to describe a command:
+//
+//	out, err := cli.Command("git").Args("clone", url).Dir(path).
+//		Env("GIT_TERMINAL_PROMPT=0").Timeout(30 * time.Second).Capture().Run(ctx)
+//
+// Every method returns a modified copy, so a Builder can be kept as a
+// reusable base and extended per call:
+//
+//	git := cli.Command("git").Dir(repo).Capture()
+//	head, _ := git.Args("rev-parse", "HEAD").Run(ctx)
+//	branch, _ := git.Args("branch", "--show-current").Run(ctx)
+type Builder struct {
+	command string
+	args    []string
+	opts    Options
+}
+
+// Command starts a Builder for the given executable.
+func Command(command string) Builder {
+	return Builder{command: command}
+}
+
+// Args appends arguments.
+func (b Builder) Args(args ...string) Builder {
+	b.args = append(slices.Clip(b.args), args...)
+	return b
+}
+
+// Dir sets the working directory.
+func (b Builder) Dir(dir string) Builder {
+	b.opts.Dir = dir
+	return b
+}
+
+// Env appends KEY=VALUE pairs to the environment.
+func (b Builder) Env(kv ...string) Builder {
+	b.opts.Env = append(slices.Clip(b.opts.Env), kv...)
+	return b
+}
+
+// CleanEnv starts from an empty environment, passing through only the
+// allowlisted parent variables (see Options.EnvAllowlist).
+func (b Builder) CleanEnv(allowlist ...string) Builder {
+	b.opts.CleanEnv = true
+	b.opts.EnvAllowlist = append(slices.Clip(b.opts.EnvAllowlist), allowlist...)
+	return b
+}
+
+// Input feeds s to the command's standard input.
+func (b Builder) Input(s string) Builder {
+	b.opts.Input = s
+	return b
+}
+
+// Stdin connects r to the command's standard input.
+func (b Builder) Stdin(r io.Reader) Builder {
+	b.opts.Stdin = r
+	return b
+}
+
+// Timeout kills the command after d (see Options.Timeout).
+func (b Builder) Timeout(d time.Duration) Builder {
+	b.opts.Timeout = d
+	return b
+}
+
+// Capture captures combined output instead of streaming it.
+func (b Builder) Capture() Builder {
+	b.opts.CaptureOutput = true
+	return b
+}
+
+// CaptureSeparate captures stdout and stderr into separate buffers.
+func (b Builder) CaptureSeparate() Builder {
+	b.opts.CaptureOutput = true
+	b.opts.SeparateStderr = true
+	return b
+}
+
+// MaxOutput caps captured output at n bytes per stream (see
+// Options.MaxOutputBytes).
+func (b Builder) MaxOutput(n int, strategy TruncateStrategy) Builder {
+	b.opts.MaxOutputBytes = n
+	b.opts.Truncate = strategy
+	return b
+}
+
+// Cache memoizes successful results for ttl (see Options.CacheTTL).
+func (b Builder) Cache(ttl time.Duration) Builder {
+	b.opts.CacheTTL = ttl
+	return b
+}
+
+// Tee captures combined output and streams it live at the same time.
+func (b Builder) Tee() Builder {
+	b.opts.CaptureOutput = true
+	b.opts.Tee = true
+	return b
+}
+
+// Stream sends output to the given writers instead of capturing it.
+// nil means os.Stdout / os.Stderr.
+func (b Builder) Stream(stdout, stderr io.Writer) Builder {
+	b.opts.CaptureOutput = false
+	b.opts.Stdout = stdout
+	b.opts.Stderr = stderr
+	return b
+}
+
+// Prefix labels streamed output lines with "[label] " (optionally colored).
+func (b Builder) Prefix(label string, color bool) Builder {
+	b.opts.Prefix = label
+	b.opts.PrefixColor = color
+	return b
+}
+
+// OnStdoutLine registers a per-line callback for standard output.
+func (b Builder) OnStdoutLine(fn func(string)) Builder {
+	b.opts.OnStdoutLine = fn
+	return b
+}
+
+// OnStderrLine registers a per-line callback for standard error.
+func (b Builder) OnStderrLine(fn func(string)) Builder {
+	b.opts.OnStderrLine = fn
+	return b
+}
+
+// Retry enables retries with the given backoff (zero fields use DefaultBackoff).
+func (b Builder) Retry(retries int, backoff Backoff, onExitCodes ...int) Builder {
+	b.opts.Retries = retries
+	b.opts.Backoff = backoff
+	b.opts.RetryOnExitCodes = append([]int(nil), onExitCodes...)
+	return b
+}
+
+// ProcessGroup kills the whole process tree on cancel/timeout.
+func (b Builder) ProcessGroup() Builder {
+	b.opts.ProcessGroup = true
+	return b
+}
+
+// DryRun logs the command instead of running it.
+func (b Builder) DryRun() Builder {
+	b.opts.DryRun = true
+	return b
+}
+
+// Log logs the command before running it.
+func (b Builder) Log() Builder {
+	b.opts.LogCommand = true
+	return b
+}
+
+// With applies arbitrary changes to the underlying Options, for settings
+// without a dedicated builder method. fn gets its own copy of every slice
+// field, so appending to or editing them doesn't affect b.
+func (b Builder) With(fn func(*Options)) Builder {
+	b.opts.Env = slices.Clone(b.opts.Env)
+	b.opts.EnvAllowlist = slices.Clone(b.opts.EnvAllowlist)
+	b.opts.RetryOnExitCodes = slices.Clone(b.opts.RetryOnExitCodes)
+	b.opts.RedactFlags = slices.Clone(b.opts.RedactFlags)
+	b.opts.RedactPatterns = slices.Clone(b.opts.RedactPatterns)
+	fn(&b.opts)
+	return b
+}
+
+// Options returns a copy of the accumulated Options.
+func (b Builder) Options() Options {
+	return b.opts
+}
+
+// Spec returns the command as a Spec, e.g. for Pipe.
+func (b Builder) Spec() Spec {
+	return Spec{Command: b.command, Args: slices.Clone(b.args), Opts: b.opts}
+}
+
+// String renders the command line, shell-quoted for the current platform
+// (see QuoteAll), so it can be logged and pasted back into a shell.
+func (b Builder) String() string {
+	return QuoteAll(append([]string{b.command}, b.args...))
+}
+
+// Run executes the command (see Run).
+func (b Builder) Run(ctx context.Context) (string, error) {
+	return Run(ctx, b.command, b.args, b.opts)
+}
+
+// Result executes the command and returns the full Result (see RunResult).
+func (b Builder) Result(ctx context.Context) (*Result, error) {
+	return RunResult(ctx, b.command, b.args, b.opts)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// resultCache memoizes successful results for Options.CacheTTL.
+var resultCache = struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}{entries: map[string]cacheEntry{}}
+
+type cacheEntry struct {
+	res     Result
+	expires time.Time
+}
+
+// ClearCache drops all results memoized via Options.CacheTTL, e.g. after
+// an operation (a commit, a checkout) that invalidates them.
+func ClearCache() {
+	resultCache.mu.Lock()
+	defer resultCache.mu.Unlock()
+	clear(resultCache.entries)
+}
+
+// cacheable reports whether a command's result may be memoized. Commands
+// reading an arbitrary Stdin can't be keyed and are never cached.
+func cacheable(opts Options) bool {
+	return opts.CacheTTL > 0 && opts.Stdin == nil
+}
+
+// cacheKey identifies a command invocation: everything that can change its
+// output.
+func cacheKey(command string, args []string, opts Options) string {
+	parts := []string{
+		command,
+		strings.Join(args, "\x00"),
+		opts.Dir,
+		strings.Join(opts.Env, "\x00"),
+		strconv.FormatBool(opts.CleanEnv),
+		strings.Join(opts.EnvAllowlist, "\x00"),
+		opts.Input,
+		strconv.FormatBool(opts.CaptureOutput),
+		strconv.FormatBool(opts.SeparateStderr),
+		strconv.FormatBool(opts.PTY),
+		strconv.Itoa(opts.MaxOutputBytes),
+		strconv.Itoa(int(opts.Truncate)),
+	}
+	if c := opts.Credential; c != nil {
+		groups := make([]string, len(c.Groups))
+		for i, g := range c.Groups {
+			groups[i] = strconv.FormatUint(uint64(g), 10)
+		}
+		parts = append(parts, fmt.Sprintf("%d:%d:%s:%t", c.UID, c.GID, strings.Join(groups, ","), c.NoSetGroups))
+	}
+	if opts.Remote != nil {
+		parts = append(parts, opts.Remote.User+"@"+opts.Remote.Host+":"+strconv.Itoa(opts.Remote.Port))
+	}
+	return strings.Join(parts, "\x01")
+}
+
+// cachedResult returns a copy of a live cached Result for key.
+func cachedResult(key string) (*Result, bool) {
+	resultCache.mu.Lock()
+	defer resultCache.mu.Unlock()
+
+	e, ok := resultCache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(resultCache.entries, key)
+		return nil, false
+	}
+	res := e.res
+	res.Cached = true
+	return &res, true
+}
+
+// storeResult memoizes res under key for ttl.
+func storeResult(key string, res *Result, ttl time.Duration) {
+	resultCache.mu.Lock()
+	defer resultCache.mu.Unlock()
+	resultCache.entries[key] = cacheEntry{res: *res, expires: time.Now().Add(ttl)}
+}
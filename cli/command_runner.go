@@ -2,12 +2,35 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 	"os/exec"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/toobprojects/go-commons/logs"
 )
 
+// ErrTimeout is returned (wrapped) when a command exceeds Options.Timeout.
+var ErrTimeout = errors.New("command timed out")
+
+// timeoutWaitDelay bounds how long Wait blocks on output pipes after a
+// timed-out command has been killed.
+const timeoutWaitDelay = 2 * time.Second
+
+// TruncateStrategy selects which part of captured output is kept when it
+// exceeds Options.MaxOutputBytes.
+type TruncateStrategy int
+
+const (
+	// KeepHead keeps the first MaxOutputBytes and drops the rest.
+	KeepHead TruncateStrategy = iota
+	// KeepTail keeps the last MaxOutputBytes, where errors usually are.
+	KeepTail
+)
+
 // Options defines how a command should be executed.
 //
 // This is designed to be reusable by any consumer of the go-commons module.
@@ -22,6 +45,34 @@ type Options struct {
 	// If nil or empty, only the inherited environment is used.
 	Env []string
 
+	// CleanEnv starts the command with an empty environment instead of the
+	// inherited one (plus EnvAllowlist and Env). Use it for hermetic builds
+	// and to keep secrets of the parent process out of children.
+	CleanEnv bool
+
+	// EnvAllowlist lists parent environment variables that are passed
+	// through; all others are dropped. Entries ending in "*" match a prefix
+	// (e.g. "LC_*"). Setting it implies CleanEnv.
+	EnvAllowlist []string
+
+	// Credential runs the command as another user and group (Unix; the
+	// parent must be privileged). Nil runs as the current user. See
+	// LookupCredential.
+	Credential *Credential
+
+	// Remote executes the command on another host over SSH instead of
+	// locally; Dir and Env then apply on the remote side. See Remote.
+	Remote *Remote
+
+	// Stdin is connected to the command's standard input.
+	// If nil (and Input is empty), the command gets no input (reads see EOF).
+	Stdin io.Reader
+
+	// Input is a convenience for feeding a fixed string to the command's
+	// standard input, e.g. a manifest for `kubectl apply -f -`.
+	// It is ignored when Stdin is set.
+	Input string
+
 	// CaptureOutput controls whether the command output is captured and
 	// returned as a string, or streamed directly to Stdout/Stderr.
 	//
@@ -30,13 +81,121 @@ type Options struct {
 	//               string will be empty.
 	CaptureOutput bool
 
+	// Tee, together with CaptureOutput, streams output live to Stdout/Stderr
+	// (default os.Stdout/os.Stderr) while also capturing it, so users see
+	// progress and the caller still gets the text.
+	Tee bool
+
+	// SeparateStderr, together with CaptureOutput, captures stdout and
+	// stderr into separate buffers (Result.Stdout / Result.Stderr) instead
+	// of one combined stream. Run then returns stdout only, which keeps
+	// structured output parseable while warnings stay available in Stderr.
+	SeparateStderr bool
+
+	// MaxOutputBytes caps how much output is kept in memory per captured
+	// stream (0 = unlimited). Excess output is discarded according to
+	// Truncate and Result.Truncated is set; streaming is not affected.
+	MaxOutputBytes int
+
+	// Truncate selects which part of the output survives MaxOutputBytes:
+	// KeepHead (default) or KeepTail.
+	Truncate TruncateStrategy
+
 	// Stdout is the destination for the command's standard output when
-	// CaptureOutput is false. If nil, os.Stdout is used.
-	Stdout *os.File
+	// CaptureOutput is false (or Tee is set): a file, a buffer, a logger, a
+	// network stream. If nil, os.Stdout is used. Writers other than
+	// *os.File are fed through a pipe by os/exec.
+	Stdout io.Writer
 
 	// Stderr is the destination for the command's standard error when
-	// CaptureOutput is false. If nil, os.Stderr is used.
-	Stderr *os.File
+	// CaptureOutput is false (or Tee is set). If nil, os.Stderr is used.
+	Stderr io.Writer
+
+	// Prefix labels every streamed (or teed) output line, e.g. "api" gives
+	// "[api] listening on :8080", so output of concurrent commands sharing
+	// a terminal stays readable. Captured output is not prefixed.
+	Prefix string
+
+	// PrefixColor colors the Prefix label with ANSI codes, picking a stable
+	// color per label like docker-compose.
+	PrefixColor bool
+
+	// OnStdoutLine / OnStderrLine are called for every line the command
+	// writes, as it is written, in addition to capturing or streaming.
+	// Trailing "\n" / "\r\n" are stripped. The two callbacks may run
+	// concurrently with each other, but each is called sequentially.
+	OnStdoutLine func(line string)
+	OnStderrLine func(line string)
+
+	// Timeout bounds how long the command may run. When it expires the
+	// process is killed and the returned error matches ErrTimeout.
+	// Zero means no timeout (only ctx applies).
+	Timeout time.Duration
+
+	// Heartbeat logs "Command still running" with the elapsed time and the
+	// last line of output every Heartbeat while the command runs, so silent
+	// long-running steps in CI logs are visibly alive. Zero disables it.
+	Heartbeat time.Duration
+
+	// PTY runs the command under a pseudo-terminal (Linux and macOS), for
+	// tools that only show colors, progress bars or prompts on a TTY.
+	// Stdout and stderr are merged by the terminal and delivered through the
+	// stdout path (capture, Stdout, OnStdoutLine). TERM defaults to
+	// xterm-256color when unset.
+	PTY bool
+
+	// PTYSize sets the terminal window size for PTY (default 24x80).
+	PTYSize *WindowSize
+
+	// ProcessGroup starts the command in its own process group (a Job Object
+	// on Windows) and kills the entire group on cancellation or timeout, so
+	// grandchildren spawned by shells don't outlive the command.
+	ProcessGroup bool
+
+	// Limits constrains the child's resources (niceness, CPU time, memory,
+	// open files); see Limits for platform support. Nil means unlimited.
+	Limits *Limits
+
+	// GracePeriod makes cancellation and timeouts graceful: the command
+	// (its whole group with ProcessGroup) first gets SIGTERM — CTRL_BREAK on
+	// Windows — and is killed only if still running after GracePeriod, so
+	// cleanup handlers get to run. Zero kills immediately.
+	GracePeriod time.Duration
+
+	// Retries is how many times a failed command is re-run (0 = no retry).
+	// Retries stop early when ctx is done. Stdin readers are consumed by the
+	// first attempt; use Input for retried commands that need input.
+	Retries int
+
+	// Backoff controls the delay between retries. Zero values use
+	// DefaultBackoff.
+	Backoff Backoff
+
+	// RetryOnExitCodes limits retries to these exit codes (e.g. 1 for a
+	// network error). If empty, any failure is retried.
+	RetryOnExitCodes []int
+
+	// CacheTTL memoizes successful results of idempotent queries (e.g.
+	// `git rev-parse HEAD`, `uname -a`) for this long; later identical calls
+	// (same command, args, dir, env, input and capture mode) return the
+	// stored Result with Cached set. Commands with Stdin are never cached.
+	// Zero disables caching; see ClearCache.
+	CacheTTL time.Duration
+
+	// DryRun logs the fully rendered command (args, dir, env) instead of
+	// executing it and returns a successful Result with DryRun set.
+	// See also SetDryRun for a package-wide switch.
+	DryRun bool
+
+	// RedactFlags lists flags whose values are masked in logs, e.g.
+	// "--password" or "token" (dashes optional). Both "--password=x" and
+	// "--password x" are masked, as are Env entries with that key. The
+	// command itself still receives the real values.
+	RedactFlags []string
+
+	// RedactPatterns masks every match in logged args and env, e.g.
+	// regexp.MustCompile(`ghp_[A-Za-z0-9]+`).
+	RedactPatterns []*regexp.Regexp
 
 	// LogCommand controls whether the executed command and its arguments
 	// are logged before execution.
@@ -49,81 +208,130 @@ type Options struct {
 // returned by the underlying exec.CommandContext invocation.
 //
 // This is the primary, reusable entry point for running native commands.
+// Use RunResult when you also need the exit code, timing or pid.
 func Run(ctx context.Context, command string, args []string, opts Options) (string, error) {
-	log := logs.WithGroup("cli").With("command", command)
+	res, err := RunResult(ctx, command, args, opts)
+	return res.Stdout, err
+}
 
-	if opts.LogCommand {
-		log.Info("Running native command",
-			"command", command,
-			"args", args,
-			"dir", opts.Dir,
-		)
+// RunResult executes a command like Run but returns a *Result with the exit
+// code, duration and pid alongside the output. The returned Result is never
+// nil, even when err is non-nil, so exit codes can be inspected on failure.
+func RunResult(ctx context.Context, command string, args []string, opts Options) (*Result, error) {
+	run := hooked(func(ctx context.Context, spec Spec) (*Result, error) {
+		return runSpec(ctx, spec.Command, spec.Args, spec.Opts)
+	})
+	res, err := run(ctx, Spec{Command: command, Args: args, Opts: withDefaults(opts)})
+	if res == nil {
+		// A hook short-circuited without a Result; keep the non-nil promise.
+		res = &Result{ExitCode: -1}
 	}
+	return res, err
+}
 
-	cmd := exec.CommandContext(ctx, command, args...)
-
-	// Working directory
-	if opts.Dir != "" {
-		cmd.Dir = opts.Dir
+// runSpec is the end of the hook chain: dry run, cache, then execution.
+func runSpec(ctx context.Context, command string, args []string, opts Options) (*Result, error) {
+	if isDryRun(opts) {
+		return dryRunResult(command, args, opts), nil
+	}
+	if !cacheable(opts) {
+		return execute(ctx, command, args, opts)
 	}
 
-	// Environment
-	if len(opts.Env) > 0 {
-		cmd.Env = append(os.Environ(), opts.Env...)
+	key := cacheKey(command, args, opts)
+	if res, ok := cachedResult(key); ok {
+		return res, nil
+	}
+	res, err := execute(ctx, command, args, opts)
+	if err == nil {
+		storeResult(key, res, opts.CacheTTL)
 	}
+	return res, err
+}
 
-	// Capture vs stream output
-	if opts.CaptureOutput {
-		out, err := cmd.CombinedOutput()
-		output := string(out)
+// execute runs the command, with retries when configured.
+func execute(ctx context.Context, command string, args []string, opts Options) (*Result, error) {
+	if opts.Retries > 0 {
+		return runWithRetry(ctx, command, args, opts)
+	}
+	return runOnce(ctx, command, args, opts)
+}
 
-		if err != nil {
-			logs.Error("Command failed",
-				"args", args,
-				"dir", opts.Dir,
-				"err", err,
-				"output", output,
-			)
-			return output, err
-		}
+// runOnce performs a single execution attempt.
+func runOnce(ctx context.Context, command string, args []string, opts Options) (*Result, error) {
+	if opts.LogCommand {
+		logCommand(command, args, opts)
+	}
 
-		logs.Debug("Command succeeded",
-			"args", args,
-			"dir", opts.Dir,
-		)
+	e, err := newExecution(ctx, command, args, opts, nil, nil)
+	if err != nil {
+		logs.Error("Command failed", "args", redactArgs(args, opts), "dir", opts.Dir, "err", err)
+		return &Result{ExitCode: -1, Attempts: 1}, err
+	}
+	if err := e.begin(); err != nil {
+		return e.finish(err)
+	}
+	return e.wait()
+}
 
-		return output, nil
+// logCommand logs the command line before execution (Options.LogCommand).
+func logCommand(command string, args []string, opts Options) {
+	attrs := []any{
+		"command", command,
+		"args", redactArgs(args, opts),
+		"dir", opts.Dir,
 	}
+	if opts.Remote != nil {
+		attrs = append(attrs, "host", opts.Remote.Host)
+	}
+	logs.WithGroup("cli").With("command", command).Info("Running native command", attrs...)
+}
 
-	// Streaming mode: attach stdout/stderr
-	if opts.Stdout != nil {
-		cmd.Stdout = opts.Stdout
-	} else {
-		cmd.Stdout = os.Stdout
+// streamWriters returns the live output destinations: Options.Stdout and
+// Options.Stderr, defaulting to the process's own streams.
+func streamWriters(opts Options) (io.Writer, io.Writer) {
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	if w := outputWriter(opts.Stdout); w != nil {
+		stdout = w
 	}
+	if w := outputWriter(opts.Stderr); w != nil {
+		stderr = w
+	}
+	return stdout, stderr
+}
 
-	if opts.Stderr != nil {
-		cmd.Stderr = opts.Stderr
-	} else {
-		cmd.Stderr = os.Stderr
+// outputWriter normalizes an Options.Stdout/Stderr value. A nil *os.File
+// stored in the interface (as older code assigning a *os.File variable may
+// do) is treated as unset, as it was when the fields were *os.File.
+func outputWriter(w io.Writer) io.Writer {
+	if f, ok := w.(*os.File); ok && f == nil {
+		return nil
 	}
+	return w
+}
 
-	err := cmd.Run()
-	if err != nil {
-		logs.Error("Command failed (streaming)",
-			"args", args,
-			"dir", opts.Dir,
-			"err", err,
-		)
-		return "", err
+// newCmd builds the exec.Cmd for command and applies the process-level
+// options (working directory, environment, stdin). Output wiring is left
+// to the caller.
+func newCmd(ctx context.Context, command string, args []string, opts Options) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, command, args...)
+
+	// Working directory
+	if opts.Dir != "" {
+		cmd.Dir = opts.Dir
 	}
 
-	logs.Debug("Command succeeded (streaming)",
-		"args", args,
-		"dir", opts.Dir,
-	)
+	// Environment
+	cmd.Env = buildEnv(opts)
 
-	return "", nil
+	// Standard input
+	if opts.Stdin != nil {
+		cmd.Stdin = opts.Stdin
+	} else if opts.Input != "" {
+		cmd.Stdin = strings.NewReader(opts.Input)
+	}
+
+	return cmd
 }
 
 // RunWithDefaults is a convenience helper for running a command with sensible defaults:
@@ -181,10 +389,18 @@ func ExecWithNativeLog(command string, commandArgs []string, targetPath string,
 
 // ExecScriptFile is a helper for running an executable script file.
 //
-// It uses the system shell (`/bin/bash`) to run the script at scriptPath
-// with the given target working directory.
+// It runs the script at scriptPath with the platform's shell (see
+// DetectShell) in the given target working directory. Use RunScriptFile
+// for explicit errors and interpreter control.
 func ExecScriptFile(scriptPath string, targetPath string, returnOutput bool) string {
-	const shell = "/bin/bash"
-
-	return Exec(shell, []string{scriptPath}, targetPath, returnOutput)
+	res, err := RunScriptFile(context.Background(), scriptPath, ScriptOptions{
+		Options: Options{
+			Dir:           targetPath,
+			CaptureOutput: returnOutput,
+		},
+	})
+	if err != nil {
+		return ""
+	}
+	return res.Stdout
 }
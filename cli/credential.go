@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"strconv"
+)
+
+// ErrCredentialUnsupported is returned when Options.Credential is used on a
+// platform without uid/gid switching (Windows).
+var ErrCredentialUnsupported = errors.New("running as another user is not supported on this platform")
+
+// Credential is the identity a child command runs as (Unix only). Changing
+// it requires the parent to be privileged, typically root dropping
+// privileges for untrusted steps.
+type Credential struct {
+	UID uint32
+	GID uint32
+
+	// Groups are the supplementary group ids. If nil (and NoSetGroups is
+	// false) the child gets no supplementary groups.
+	Groups []uint32
+
+	// NoSetGroups keeps the parent's supplementary groups.
+	NoSetGroups bool
+}
+
+// LookupCredential resolves username to a Credential with the user's
+// primary group and supplementary groups, e.g. LookupCredential("nobody").
+func LookupCredential(username string) (*Credential, error) {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return nil, fmt.Errorf("lookup user %q: %w", username, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("user %q: %w: uid %q", username, ErrCredentialUnsupported, u.Uid)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("user %q: %w: gid %q", username, ErrCredentialUnsupported, u.Gid)
+	}
+
+	cred := &Credential{UID: uint32(uid), GID: uint32(gid)}
+	gids, err := u.GroupIds()
+	if err != nil {
+		return nil, fmt.Errorf("groups of user %q: %w", username, err)
+	}
+	for _, g := range gids {
+		if n, err := strconv.ParseUint(g, 10, 32); err == nil {
+			cred.Groups = append(cred.Groups, uint32(n))
+		}
+	}
+	return cred, nil
+}
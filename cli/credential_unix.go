@@ -0,0 +1,25 @@
+//go:build unix
+
+package cli
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setCredential makes cmd run as c (no-op when c is nil).
+func setCredential(cmd *exec.Cmd, c *Credential) error {
+	if c == nil {
+		return nil
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = &syscall.Credential{
+		Uid:         c.UID,
+		Gid:         c.GID,
+		Groups:      c.Groups,
+		NoSetGroups: c.NoSetGroups,
+	}
+	return nil
+}
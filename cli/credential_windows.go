@@ -0,0 +1,11 @@
+package cli
+
+import "os/exec"
+
+// setCredential fails on Windows when a credential is requested.
+func setCredential(_ *exec.Cmd, c *Credential) error {
+	if c == nil {
+		return nil
+	}
+	return ErrCredentialUnsupported
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"sync/atomic"
+
+	"github.com/toobprojects/go-commons/logs"
+)
+
+// dryRun is the package-wide dry-run switch (see SetDryRun).
+var dryRun atomic.Bool
+
+// SetDryRun turns package-wide dry-run mode on or off. While on, every Run
+// logs the fully rendered command instead of executing it, as if each call
+// had Options.DryRun set. Handy for wiring a --dry-run CLI flag once.
+func SetDryRun(enabled bool) {
+	dryRun.Store(enabled)
+}
+
+// DryRunEnabled reports whether package-wide dry-run mode is on.
+func DryRunEnabled() bool {
+	return dryRun.Load()
+}
+
+func isDryRun(opts Options) bool {
+	return opts.DryRun || dryRun.Load()
+}
+
+// dryRunResult logs what would have been executed and returns a synthetic
+// successful Result.
+func dryRunResult(command string, args []string, opts Options) *Result {
+	logs.WithGroup("cli").Info("Dry run: command not executed",
+		"command", command,
+		"args", redactArgs(args, opts),
+		"dir", opts.Dir,
+		"env", redactEnv(opts.Env, opts),
+		"clean_env", opts.CleanEnv || len(opts.EnvAllowlist) > 0,
+	)
+	return &Result{DryRun: true}
+}
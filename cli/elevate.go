@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrPasswordRequired is returned by RunElevated in non-interactive mode
+	// when sudo can't run without prompting for a password (or the user may
+	// not use sudo at all).
+	ErrPasswordRequired = errors.New("sudo: a password is required")
+
+	// ErrElevationUnavailable is returned when the process is not privileged
+	// and sudo is not available (including on Windows).
+	ErrElevationUnavailable = errors.New("privilege elevation is not available")
+)
+
+// ElevatedOptions controls RunElevated.
+type ElevatedOptions struct {
+	// Options are the regular run options.
+	Options
+
+	// NonInteractive passes -n to sudo, so it fails with ErrPasswordRequired
+	// instead of blocking on a password prompt. It is checked up front with
+	// `sudo -n true`, and the command isn't run if that fails. Recommended
+	// for automation.
+	NonInteractive bool
+
+	// User runs the command as this user (sudo -u) instead of root.
+	User string
+
+	// PreserveEnv passes -E to sudo to keep the caller's environment
+	// (subject to the sudoers policy).
+	PreserveEnv bool
+}
+
+// IsElevated reports whether the current process already runs as root.
+// It always returns false on Windows.
+func IsElevated() bool {
+	return runtime.GOOS != "windows" && os.Geteuid() == 0
+}
+
+// CanElevate reports whether RunElevated can run commands without a
+// password prompt: either the process is root, or `sudo -n true` succeeds
+// (cached credentials or NOPASSWD).
+func CanElevate(ctx context.Context) bool {
+	if IsElevated() {
+		return true
+	}
+	if _, err := exec.LookPath("sudo"); err != nil {
+		return false
+	}
+	return sudoCheck(ctx, "") == nil
+}
+
+// RunElevated runs command with root privileges. When the process is
+// already root (and no other User is requested) the command runs directly;
+// otherwise it is prefixed with sudo.
+func RunElevated(ctx context.Context, command string, args []string, opts ElevatedOptions) (*Result, error) {
+	if IsElevated() && opts.User == "" {
+		return RunResult(ctx, command, args, opts.Options)
+	}
+
+	if runtime.GOOS == "windows" {
+		return &Result{ExitCode: -1}, fmt.Errorf("%w: run the process as Administrator", ErrElevationUnavailable)
+	}
+	if _, err := exec.LookPath("sudo"); err != nil && !isDryRun(opts.Options) {
+		return &Result{ExitCode: -1}, fmt.Errorf("%w: sudo not found: %w", ErrElevationUnavailable, err)
+	}
+
+	// Ask sudo itself, through `sudo -n true`'s exit status, whether it
+	// would prompt: its messages are localized, and once the command runs
+	// sudo's failures can't be told apart from the command's.
+	if opts.NonInteractive && !isDryRun(opts.Options) {
+		if err := sudoCheck(ctx, opts.User); err != nil {
+			return &Result{ExitCode: -1}, fmt.Errorf("%w: %w", ErrPasswordRequired, err)
+		}
+	}
+
+	sudoArgs := sudoFlags(opts)
+	sudoArgs = append(sudoArgs, "--", command)
+	sudoArgs = append(sudoArgs, args...)
+	return RunResult(ctx, "sudo", sudoArgs, opts.Options)
+}
+
+// sudoCheck runs `sudo -n [-u user] true` directly (no defaults, hooks or
+// dry-run) and fails if sudo can't run it without a password.
+func sudoCheck(ctx context.Context, user string) error {
+	args := []string{"-n"}
+	if user != "" {
+		args = append(args, "-u", user)
+	}
+	out, err := exec.CommandContext(ctx, "sudo", append(args, "true")...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+func sudoFlags(opts ElevatedOptions) []string {
+	var flags []string
+	if opts.NonInteractive {
+		flags = append(flags, "-n")
+	}
+	if opts.PreserveEnv {
+		flags = append(flags, "-E")
+	}
+	if opts.User != "" {
+		flags = append(flags, "-u", opts.User)
+	}
+	return flags
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// buildEnv returns the environment for the child process, or nil to inherit
+// the parent's environment unchanged.
+func buildEnv(opts Options) []string {
+	if !opts.CleanEnv && len(opts.EnvAllowlist) == 0 {
+		if len(opts.Env) == 0 {
+			return nil
+		}
+		return append(os.Environ(), opts.Env...)
+	}
+
+	// Non-nil even when empty: a nil Env would inherit everything.
+	env := make([]string, 0, len(opts.EnvAllowlist)+len(opts.Env))
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if envAllowed(key, opts.EnvAllowlist) {
+			env = append(env, kv)
+		}
+	}
+	return append(env, opts.Env...)
+}
+
+// envAllowed reports whether key matches an allowlist entry. Entries may end
+// in "*" to match a prefix (e.g. "LC_*"). Matching is case-insensitive on
+// Windows, where environment variable names are.
+func envAllowed(key string, allowlist []string) bool {
+	for _, pattern := range allowlist {
+		k, p := key, pattern
+		if runtime.GOOS == "windows" {
+			k, p = strings.ToUpper(k), strings.ToUpper(p)
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(k, prefix) {
+				return true
+			}
+		} else if k == p {
+			return true
+		}
+	}
+	return false
+}
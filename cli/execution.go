@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"time"
+
+	"github.com/toobprojects/go-commons/logs"
+)
+
+// execution is a single, fully wired command invocation. It is shared by
+// the synchronous Run* functions and by Start.
+type execution struct {
+	command string
+	args    []string
+	opts    Options
+	log     *slog.Logger
+
+	ctx    context.Context // caller's context
+	runCtx context.Context // ctx plus Options.Timeout
+	cancel context.CancelFunc
+
+	cmd            *exec.Cmd
+	stdout, stderr syncBuffer
+	lineWriters    []*lineWriter
+	pty            *ptySession
+	pg             *processGroup
+	hb             *heartbeat
+	grace          *gracefulStop
+	start          time.Time
+}
+
+// newExecution builds the command and wires output according to opts.
+// extraOut / extraErr (may be nil) additionally receive the output streams.
+func newExecution(ctx context.Context, command string, args []string, opts Options, extraOut, extraErr io.Writer) (*execution, error) {
+	e := &execution{
+		command: command,
+		args:    args,
+		opts:    opts,
+		log:     logs.WithGroup("cli").With("command", command),
+		ctx:     ctx,
+		runCtx:  ctx,
+	}
+
+	if opts.Timeout > 0 {
+		e.runCtx, e.cancel = context.WithTimeout(ctx, opts.Timeout)
+	}
+
+	local := opts
+	if opts.Remote != nil {
+		var err error
+		if command, args, err = opts.Remote.sshCommand(command, args, opts); err != nil {
+			e.release()
+			return nil, err
+		}
+		local = opts.Remote.localOptions(opts)
+	}
+
+	cmd := newCmd(e.runCtx, command, args, local)
+	if opts.Timeout > 0 {
+		// Don't hang on pipes still held open by orphaned grandchildren once killed.
+		cmd.WaitDelay = timeoutWaitDelay
+	}
+	e.cmd = cmd
+	if err := setCredential(cmd, opts.Credential); err != nil {
+		e.release()
+		return nil, err
+	}
+	if !opts.Limits.empty() {
+		if err := prepareLimits(cmd, opts.Limits); err != nil {
+			e.release()
+			return nil, err
+		}
+	}
+
+	// Capture vs stream output
+	if opts.CaptureOutput {
+		if opts.MaxOutputBytes > 0 {
+			e.stdout.setLimit(opts.MaxOutputBytes, opts.Truncate)
+			e.stderr.setLimit(opts.MaxOutputBytes, opts.Truncate)
+		}
+		cmd.Stdout = &e.stdout
+		if opts.SeparateStderr {
+			cmd.Stderr = &e.stderr
+		} else {
+			cmd.Stderr = &e.stdout
+		}
+
+		// Tee: also stream live while capturing
+		if opts.Tee {
+			streamOut, streamErr := e.streamWriters()
+			cmd.Stdout = io.MultiWriter(cmd.Stdout, streamOut)
+			cmd.Stderr = io.MultiWriter(cmd.Stderr, streamErr)
+		}
+	} else {
+		// Streaming mode: attach stdout/stderr
+		cmd.Stdout, cmd.Stderr = e.streamWriters()
+	}
+
+	if extraOut != nil {
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, extraOut)
+	}
+	if extraErr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, extraErr)
+	}
+
+	// Line callbacks
+	if opts.OnStdoutLine != nil {
+		lw := newLineWriter(opts.OnStdoutLine)
+		e.lineWriters = append(e.lineWriters, lw)
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, lw)
+	}
+	if opts.OnStderrLine != nil {
+		lw := newLineWriter(opts.OnStderrLine)
+		e.lineWriters = append(e.lineWriters, lw)
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, lw)
+	}
+
+	// Heartbeat: remember the last line of either stream.
+	if opts.Heartbeat > 0 {
+		e.hb = newHeartbeat(opts.Heartbeat)
+		outLW, errLW := newLineWriter(e.hb.record), newLineWriter(e.hb.record)
+		e.lineWriters = append(e.lineWriters, outLW, errLW)
+		cmd.Stdout = io.MultiWriter(cmd.Stdout, outLW)
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, errLW)
+	}
+
+	// Pseudo-terminal: the terminal merges stderr into stdout.
+	if opts.PTY {
+		pty, err := newPTY(cmd, opts, cmd.Stdout)
+		if err != nil {
+			e.release()
+			return nil, fmt.Errorf("allocate pty: %w", err)
+		}
+		e.pty = pty
+	}
+
+	if opts.ProcessGroup {
+		e.pg = newProcessGroup(cmd)
+	}
+	if opts.GracePeriod > 0 {
+		prepareTerminate(cmd)
+		e.grace = &gracefulStop{e: e, grace: opts.GracePeriod}
+		e.grace.install()
+	}
+	return e, nil
+}
+
+// streamWriters returns the live output destinations (see the package
+// streamWriters), prefixed per line when Options.Prefix is set.
+func (e *execution) streamWriters() (io.Writer, io.Writer) {
+	stdout, stderr := streamWriters(e.opts)
+	if e.opts.Prefix == "" {
+		return stdout, stderr
+	}
+
+	prefix := linePrefix(e.opts.Prefix, e.opts.PrefixColor)
+	out, errw := newPrefixWriter(stdout, prefix), newPrefixWriter(stderr, prefix)
+	e.lineWriters = append(e.lineWriters, out, errw)
+	return out, errw
+}
+
+// begin starts the process and runs the post-start hooks.
+func (e *execution) begin() error {
+	e.start = time.Now()
+	if err := e.cmd.Start(); err != nil {
+		if e.pty != nil {
+			e.pty.abort()
+		}
+		return err
+	}
+
+	if e.pty != nil {
+		e.pty.afterStart()
+	}
+	if e.pg != nil {
+		if err := e.pg.afterStart(); err != nil {
+			e.log.Warn("Could not attach process group", "err", err)
+		}
+	}
+	if e.hb != nil {
+		go e.hb.run(e.log, e.start)
+	}
+	if !e.opts.Limits.empty() {
+		if err := applyLimits(e.cmd.Process, e.opts.Limits); err != nil {
+			// Never leave a process running without the limits it asked for.
+			_ = e.cmd.Process.Kill()
+			_ = e.cmd.Wait()
+			if e.pty != nil {
+				e.pty.finish()
+			}
+			return fmt.Errorf("apply limits: %w", err)
+		}
+	}
+	return nil
+}
+
+// wait waits for the started process and returns its Result.
+func (e *execution) wait() (*Result, error) {
+	err := e.cmd.Wait()
+	if e.pty != nil {
+		e.pty.finish()
+	}
+	return e.finish(err)
+}
+
+// finish flushes callbacks, releases resources and builds the Result.
+// err is the error from Start or Wait.
+func (e *execution) finish(err error) (*Result, error) {
+	for _, lw := range e.lineWriters {
+		lw.Flush()
+	}
+
+	res := &Result{
+		Stdout:    e.stdout.String(),
+		Stderr:    e.stderr.String(),
+		ExitCode:  exitCode(e.cmd, err),
+		Attempts:  1,
+		Truncated: e.stdout.Truncated() || e.stderr.Truncated(),
+	}
+	if !e.start.IsZero() {
+		res.Duration = time.Since(e.start)
+	}
+	if e.cmd.Process != nil {
+		res.Pid = e.cmd.Process.Pid
+	}
+
+	err = newExitError(err, res)
+
+	// Distinguish our own timeout from the caller cancelling ctx.
+	if err != nil && e.opts.Timeout > 0 && e.ctx.Err() == nil && errors.Is(e.runCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %s: %w", ErrTimeout, e.opts.Timeout, err)
+	}
+	e.release()
+
+	if err != nil {
+		logs.Error("Command failed",
+			"args", redactArgs(e.args, e.opts),
+			"dir", e.opts.Dir,
+			"exit_code", res.ExitCode,
+			"err", err,
+			"output", res.Stdout,
+			"stderr", res.Stderr,
+		)
+		return res, err
+	}
+
+	logs.Debug("Command succeeded",
+		"args", redactArgs(e.args, e.opts),
+		"dir", e.opts.Dir,
+		"duration", res.Duration,
+	)
+	return res, nil
+}
+
+func (e *execution) release() {
+	if e.grace != nil {
+		e.grace.release()
+	}
+	if e.hb != nil {
+		e.hb.stop()
+	}
+	if e.pg != nil {
+		e.pg.close()
+	}
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
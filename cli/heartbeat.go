@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// heartbeat periodically logs that a command is still running, together
+// with its most recent line of output (Options.Heartbeat).
+type heartbeat struct {
+	interval time.Duration
+	last     atomic.Pointer[string]
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newHeartbeat(interval time.Duration) *heartbeat {
+	return &heartbeat{interval: interval, done: make(chan struct{})}
+}
+
+// record is the line callback remembering the latest non-empty line. For
+// progress bars redrawn with "\r", only the last redraw counts.
+func (h *heartbeat) record(line string) {
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	if line = strings.TrimSpace(line); line != "" {
+		h.last.Store(&line)
+	}
+}
+
+// run logs every interval until stop is called.
+func (h *heartbeat) run(log *slog.Logger, start time.Time) {
+	t := time.NewTicker(h.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-h.done:
+			return
+		case now := <-t.C:
+			attrs := []any{"elapsed", now.Sub(start).Round(time.Second).String()}
+			if last := h.last.Load(); last != nil {
+				attrs = append(attrs, "last_output", *last)
+			}
+			log.Info("Command still running", attrs...)
+		}
+	}
+}
+
+func (h *heartbeat) stop() {
+	h.stopOnce.Do(func() { close(h.done) })
+}
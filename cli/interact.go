@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"runtime"
+	"sync"
+
+	"github.com/toobprojects/go-commons/logs"
+)
+
+// interactWindow is how much recent output is kept for prompt matching.
+const interactWindow = 4 << 10
+
+// Prompt is an expect-style rule for Interact: whenever the command's
+// output matches Match, Response is typed in.
+type Prompt struct {
+	// Match is tested against the output received since the previous
+	// response (at most the last 4 KiB).
+	Match *regexp.Regexp
+
+	// Response is sent followed by a newline.
+	Response string
+
+	// Secret keeps Response out of the logs (passwords, passphrases).
+	Secret bool
+
+	// Once disables the rule after its first response.
+	Once bool
+}
+
+// Expect returns a Prompt answering response whenever the regular
+// expression pattern matches. It panics if pattern does not compile.
+func Expect(pattern, response string) Prompt {
+	return Prompt{Match: regexp.MustCompile(pattern), Response: response}
+}
+
+// InteractOptions controls Interact.
+type InteractOptions struct {
+	// Options are the regular run options. Stdin and Input are ignored;
+	// the command's input is driven by Prompts.
+	Options
+
+	// Prompts are checked in order after each chunk of output; the first
+	// matching rule responds.
+	Prompts []Prompt
+
+	// NoPTY uses plain pipes instead of a pseudo-terminal. A PTY is used by
+	// default on Linux and macOS because many tools only prompt on a TTY
+	// (and read passwords from it); elsewhere pipes are always used.
+	NoPTY bool
+}
+
+// Interact runs an interactive command, answering its prompts according to
+// opts.Prompts — for legacy installers, `ssh-keygen`, confirmations and the
+// like that would otherwise need `expect`. Use Options.Timeout to bound
+// sessions that stop at an unexpected prompt.
+func Interact(ctx context.Context, command string, args []string, opts InteractOptions) (*Result, error) {
+	run := withDefaults(opts.Options)
+	if isDryRun(run) {
+		return dryRunResult(command, args, run), nil
+	}
+	if run.LogCommand {
+		logCommand(command, args, run)
+	}
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return &Result{ExitCode: -1, Attempts: 1}, fmt.Errorf("create stdin pipe: %w", err)
+	}
+	defer stdinR.Close()
+	defer stdinW.Close()
+
+	run.Stdin = stdinR
+	run.Input = ""
+	run.PTY = !opts.NoPTY && (runtime.GOOS == "linux" || runtime.GOOS == "darwin")
+
+	m := &promptMatcher{
+		prompts: append([]Prompt(nil), opts.Prompts...),
+		stdin:   stdinW,
+		log:     logs.WithGroup("cli").With("command", command),
+	}
+
+	e, err := newExecution(ctx, command, args, run, m, m)
+	if err != nil {
+		logs.Error("Command failed", "args", redactArgs(args, run), "dir", run.Dir, "err", err)
+		return &Result{ExitCode: -1, Attempts: 1}, err
+	}
+	if err := e.begin(); err != nil {
+		return e.finish(err)
+	}
+	if !run.PTY {
+		// The child holds its own copy of the read end.
+		_ = stdinR.Close()
+	}
+
+	res, err := e.wait()
+	// Unblocks the PTY input pump.
+	_ = stdinW.Close()
+	return res, err
+}
+
+// promptMatcher watches command output and types responses into stdin.
+type promptMatcher struct {
+	mu      sync.Mutex
+	prompts []Prompt
+	used    []bool
+	window  []byte
+	stdin   io.Writer
+	log     *slog.Logger
+}
+
+func (m *promptMatcher) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.used == nil {
+		m.used = make([]bool, len(m.prompts))
+	}
+
+	m.window = append(m.window, p...)
+	if over := len(m.window) - interactWindow; over > 0 {
+		m.window = append(m.window[:0], m.window[over:]...)
+	}
+
+	for i, pr := range m.prompts {
+		if m.used[i] || pr.Match == nil || !pr.Match.Match(m.window) {
+			continue
+		}
+		if pr.Once {
+			m.used[i] = true
+		}
+		// Only output after this response can trigger the next one.
+		m.window = m.window[:0]
+
+		shown := pr.Response
+		if pr.Secret {
+			shown = "[REDACTED]"
+		}
+		m.log.Debug("Answering prompt", "match", pr.Match.String(), "response", shown)
+
+		// A failed write means the command is gone; Wait reports why.
+		_, _ = io.WriteString(m.stdin, pr.Response+"\n")
+		break
+	}
+	return len(p), nil
+}
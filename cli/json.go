@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/toobprojects/go-commons/fileio"
+)
+
+// ErrInvalidJSON is returned (wrapped) by RunJSON when the command's stdout
+// cannot be decoded.
+var ErrInvalidJSON = errors.New("invalid JSON output")
+
+// jsonExcerptLen caps how much of the offending output is quoted in errors.
+const jsonExcerptLen = 200
+
+// RunJSON runs the command, captures stdout (stderr is kept apart so warnings
+// don't corrupt the payload) and decodes it into T with the fileio parser,
+// e.g. for `kubectl get pods -o json` or `docker inspect`. parseOpts are
+// passed through to fileio (e.g. fileio.WithStrict()).
+//
+// Decode errors match ErrInvalidJSON and quote the start of the output.
+func RunJSON[T any](ctx context.Context, command string, args []string, opts Options, parseOpts ...fileio.Option) (T, error) {
+	var zero T
+
+	opts.CaptureOutput = true
+	opts.SeparateStderr = true
+
+	res, err := RunResult(ctx, command, args, opts)
+	if err != nil {
+		if stderr := strings.TrimSpace(res.Stderr); stderr != "" {
+			return zero, fmt.Errorf("%s: %w: %s", command, err, excerpt(stderr))
+		}
+		return zero, fmt.Errorf("%s: %w", command, err)
+	}
+	if res.DryRun {
+		return zero, nil
+	}
+
+	out := strings.TrimSpace(res.Stdout)
+	if out == "" {
+		return zero, fmt.Errorf("%s: %w: empty output", command, ErrInvalidJSON)
+	}
+	v, err := fileio.ParseString[T](out, ".json", parseOpts...)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w: %w (output: %q)", command, ErrInvalidJSON, err, excerpt(out))
+	}
+	return v, nil
+}
+
+// excerpt shortens s to jsonExcerptLen bytes for error messages.
+func excerpt(s string) string {
+	if len(s) <= jsonExcerptLen {
+		return s
+	}
+	return s[:jsonExcerptLen] + "..."
+}
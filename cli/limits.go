@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrLimitsUnsupported is returned when a requested resource limit cannot
+// be enforced on this platform.
+var ErrLimitsUnsupported = errors.New("resource limit is not supported on this platform")
+
+// Limits constrains the resources of a child process, e.g. to contain
+// runaway or untrusted build steps. Zero fields are not limited.
+//
+// How they are applied depends on the platform:
+//
+//	Linux:     rlimits before exec, via a /bin/sh shim running ulimit;
+//	           Nice with setpriority(2) right after the start
+//	macOS/BSD: Nice only, right after the start
+//	Windows:   a Job Object (CPUTime, Memory; Nice maps to a priority
+//	           class), assigned right after the start
+//
+// Limits set after the start race with the child: it runs for a moment
+// without them, and on Windows a child it spawns in that moment escapes the
+// Job Object. A limit that can't be applied fails the run (and kills the
+// process) rather than running it unconstrained.
+type Limits struct {
+	// Nice is the scheduling niceness, from -20 (highest priority, needs
+	// privileges) to 19 (lowest).
+	Nice int
+
+	// CPUTime caps the CPU time the process may consume (RLIMIT_CPU); the
+	// process is killed once it is exceeded.
+	CPUTime time.Duration
+
+	// Memory caps the address space of the process in bytes (RLIMIT_AS;
+	// committed memory on Windows).
+	Memory uint64
+
+	// OpenFiles caps the number of open file descriptors (RLIMIT_NOFILE).
+	// Not supported on Windows.
+	OpenFiles uint64
+}
+
+func (l *Limits) empty() bool {
+	return l == nil || *l == Limits{}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// limitShell runs the rlimit shim; see prepareLimits.
+const limitShell = "/bin/sh"
+
+// prepareLimits makes cmd start under l's rlimits. Setting them with
+// prlimit(2) after Start would leave the child running unconstrained until
+// then, so cmd is instead started through a shell that sets them with
+// ulimit (soft and hard) and execs the real program in the same process.
+func prepareLimits(cmd *exec.Cmd, l *Limits) error {
+	var ulimits []string
+	if l.CPUTime > 0 {
+		// RLIMIT_CPU has one-second granularity; round up so tiny limits still apply.
+		secs := uint64((l.CPUTime + 999_999_999) / 1_000_000_000)
+		ulimits = append(ulimits, "ulimit -t "+strconv.FormatUint(secs, 10))
+	}
+	if l.Memory > 0 {
+		kib := (l.Memory + 1023) / 1024 // ulimit -v counts KiB
+		ulimits = append(ulimits, "ulimit -v "+strconv.FormatUint(kib, 10))
+	}
+	if l.OpenFiles > 0 {
+		ulimits = append(ulimits, "ulimit -n "+strconv.FormatUint(l.OpenFiles, 10))
+	}
+	if len(ulimits) == 0 || cmd.Err != nil {
+		return nil // nothing to set, or Start fails anyway
+	}
+	if _, err := os.Stat(limitShell); err != nil {
+		return fmt.Errorf("%w: %v", ErrLimitsUnsupported, err)
+	}
+
+	// 126 is what shells exit with when a command can't be run; the
+	// program never starts if a limit can't be set.
+	script := strings.Join(ulimits, " && ") + ` || exit 126; exec "$@"`
+	cmd.Args = append([]string{"sh", "-c", script, "sh", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = limitShell
+	return nil
+}
+
+// applyLimits sets the niceness of the running process p. Unlike the
+// rlimits it can only be set after the start, so the program's first
+// moments run at the default priority.
+func applyLimits(p *os.Process, l *Limits) error {
+	if l.Nice != 0 {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, p.Pid, l.Nice); err != nil {
+			return fmt.Errorf("nice: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build unix && !linux
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// prepareLimits rejects rlimits: only Nice is supported here.
+func prepareLimits(_ *exec.Cmd, l *Limits) error {
+	if l.CPUTime > 0 || l.Memory > 0 || l.OpenFiles > 0 {
+		return fmt.Errorf("%w: only Nice is available", ErrLimitsUnsupported)
+	}
+	return nil
+}
+
+// applyLimits sets l on the running process p.
+func applyLimits(p *os.Process, l *Limits) error {
+	if l.Nice != 0 {
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, p.Pid, l.Nice); err != nil {
+			return fmt.Errorf("nice: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+var procSetInformationJobObject = modkernel32.NewProc("SetInformationJobObject")
+
+const (
+	jobObjectExtendedLimitInformation = 9
+
+	jobObjectLimitProcessTime   = 0x00000002
+	jobObjectLimitPriorityClass = 0x00000020
+	jobObjectLimitProcessMemory = 0x00000100
+
+	idlePriorityClass        = 0x00000040
+	belowNormalPriorityClass = 0x00004000
+	normalPriorityClass      = 0x00000020
+	aboveNormalPriorityClass = 0x00008000
+	highPriorityClass        = 0x00000080
+)
+
+// jobObjectExtendedLimit mirrors JOBOBJECT_EXTENDED_LIMIT_INFORMATION.
+type jobObjectExtendedLimit struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+	IoInfo                  [6]uint64
+	ProcessMemoryLimit      uintptr
+	JobMemoryLimit          uintptr
+	PeakProcessMemoryUsed   uintptr
+	PeakJobMemoryUsed       uintptr
+}
+
+// prepareLimits reports limits that can't be enforced before anything runs.
+func prepareLimits(_ *exec.Cmd, l *Limits) error {
+	if l.OpenFiles > 0 {
+		return fmt.Errorf("%w: OpenFiles", ErrLimitsUnsupported)
+	}
+	return nil
+}
+
+// applyLimits puts p in a dedicated Job Object carrying l. Jobs nest, so
+// this works alongside Options.ProcessGroup. The limits outlive our handle.
+func applyLimits(p *os.Process, l *Limits) error {
+	var info jobObjectExtendedLimit
+	if l.CPUTime > 0 {
+		info.LimitFlags |= jobObjectLimitProcessTime
+		info.PerProcessUserTimeLimit = int64(l.CPUTime / 100) // 100ns units
+	}
+	if l.Memory > 0 {
+		info.LimitFlags |= jobObjectLimitProcessMemory
+		info.ProcessMemoryLimit = uintptr(l.Memory)
+	}
+	if l.Nice != 0 {
+		info.LimitFlags |= jobObjectLimitPriorityClass
+		info.PriorityClass = priorityClass(l.Nice)
+	}
+
+	job, _, err := procCreateJobObjectW.Call(0, 0)
+	if job == 0 {
+		return fmt.Errorf("create job object: %w", err)
+	}
+	defer func() { _ = syscall.CloseHandle(syscall.Handle(job)) }()
+
+	r, _, err := procSetInformationJobObject.Call(job, jobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)), unsafe.Sizeof(info))
+	if r == 0 {
+		return fmt.Errorf("set job limits: %w", err)
+	}
+
+	ph, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(p.Pid))
+	if err != nil {
+		return fmt.Errorf("open process: %w", err)
+	}
+	defer func() { _ = syscall.CloseHandle(ph) }()
+
+	r, _, err = procAssignProcessToJobObject.Call(job, uintptr(ph))
+	if r == 0 {
+		return fmt.Errorf("assign job object: %w", err)
+	}
+	return nil
+}
+
+// priorityClass maps a Unix niceness to the closest Windows priority class.
+func priorityClass(nice int) uint32 {
+	switch {
+	case nice <= -10:
+		return highPriorityClass
+	case nice < 0:
+		return aboveNormalPriorityClass
+	case nice >= 10:
+		return idlePriorityClass
+	case nice > 0:
+		return belowNormalPriorityClass
+	default:
+		return normalPriorityClass
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// RunFunc executes a command described by spec. It is the signature hooks
+// wrap (see Use).
+type RunFunc func(ctx context.Context, spec Spec) (*Result, error)
+
+// Hook is run middleware: it receives the next RunFunc in the chain and
+// returns one that wraps it, so it can act before and after execution,
+// change the Spec (e.g. force DryRun) or short-circuit entirely.
+type Hook func(next RunFunc) RunFunc
+
+var global struct {
+	mu       sync.RWMutex
+	defaults Options
+	hooks    []Hook
+}
+
+// SetDefaults sets package-wide default Options. Every Run, RunResult,
+// Start, Interact and Pipe call starts from d: fields left at their zero
+// value in the call's Options take the default, while Env, EnvAllowlist,
+// RedactFlags and RedactPatterns are combined (defaults first). Since a
+// false bool is indistinguishable from unset, a bool defaulted to true
+// can't be turned off per call. SetDefaults(Options{}) clears them.
+func SetDefaults(d Options) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.defaults = d
+}
+
+// Use appends hooks to the middleware chain run around every RunResult
+// (and thus Run, RunJSON, RunAll, Builder and Session runs). The first hook
+// registered is the outermost. Start, Interact and Pipe are not hooked.
+func Use(hooks ...Hook) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.hooks = append(global.hooks, hooks...)
+}
+
+// ResetHooks removes all hooks registered with Use.
+func ResetHooks() {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.hooks = nil
+}
+
+// withDefaults merges the package defaults into opts.
+func withDefaults(opts Options) Options {
+	global.mu.RLock()
+	d := global.defaults
+	global.mu.RUnlock()
+
+	merged := opts
+	merged.Env = concat(d.Env, opts.Env)
+	merged.EnvAllowlist = concat(d.EnvAllowlist, opts.EnvAllowlist)
+	merged.RedactFlags = concat(d.RedactFlags, opts.RedactFlags)
+	merged.RedactPatterns = concat(d.RedactPatterns, opts.RedactPatterns)
+
+	dv := reflect.ValueOf(d)
+	mv := reflect.ValueOf(&merged).Elem()
+	for i := range mv.NumField() {
+		if f := mv.Field(i); f.IsZero() {
+			f.Set(dv.Field(i))
+		}
+	}
+	return merged
+}
+
+// concat returns a followed by b without aliasing either.
+func concat[S ~[]E, E any](a, b S) S {
+	if len(a) == 0 {
+		return b
+	}
+	return append(slices.Clip(a), b...)
+}
+
+// hooked wraps fn in the registered hooks.
+func hooked(fn RunFunc) RunFunc {
+	global.mu.RLock()
+	hooks := global.hooks
+	global.mu.RUnlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		fn = hooks[i](fn)
+	}
+	return fn
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// lineWriter is an io.Writer that calls fn for every complete line written
+// to it (without the trailing newline or carriage return). A final partial
+// line is delivered by Flush.
+type lineWriter struct {
+	mu  sync.Mutex
+	fn  func(string)
+	buf []byte
+}
+
+func newLineWriter(fn func(string)) *lineWriter {
+	return &lineWriter{fn: fn}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.fn(string(bytes.TrimSuffix(w.buf[:i], []byte{'\r'})))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush delivers any buffered partial line.
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.fn(string(bytes.TrimSuffix(w.buf, []byte{'\r'})))
+		w.buf = nil
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads. os/exec
+// copies stdout and stderr in separate goroutines, and Start lets callers
+// read captured output while the command is still running.
+//
+// With a limit set (Options.MaxOutputBytes) it retains at most limit bytes,
+// either the first or the last ones, and records that output was dropped.
+type syncBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	limit     int
+	keepTail  bool
+	truncated bool
+}
+
+func (b *syncBuffer) setLimit(limit int, strategy TruncateStrategy) {
+	b.limit = limit
+	b.keepTail = strategy == KeepTail
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+
+	if !b.keepTail {
+		room := b.limit - b.buf.Len()
+		if len(p) > room {
+			b.truncated = true
+			b.buf.Write(p[:max(room, 0)])
+			return len(p), nil
+		}
+		return b.buf.Write(p)
+	}
+
+	b.buf.Write(p)
+	if over := b.buf.Len() - b.limit; over > 0 {
+		b.truncated = true
+		// Compact only once the buffer doubles so discarding stays amortized O(n).
+		if b.buf.Len() >= 2*b.limit {
+			b.buf.Next(over)
+			tail := bytes.Clone(b.buf.Bytes())
+			b.buf.Reset()
+			b.buf.Write(tail)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data := b.buf.Bytes()
+	if b.limit > 0 && len(data) > b.limit {
+		data = data[len(data)-b.limit:]
+	}
+	return string(data)
+}
+
+// Truncated reports whether output was dropped because of the limit.
+func (b *syncBuffer) Truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.truncated
+}
+
+// liveBuffer records a stream and lets any number of readers follow it from
+// the beginning, blocking until more data arrives or the stream is closed.
+// Writers never block, so a slow (or absent) reader can't stall the command.
+type liveBuffer struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	data   []byte
+	closed bool
+}
+
+func newLiveBuffer() *liveBuffer {
+	b := &liveBuffer{}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *liveBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	b.data = append(b.data, p...)
+	b.mu.Unlock()
+	b.cond.Broadcast()
+	return len(p), nil
+}
+
+// Close marks the end of the stream; blocked readers return io.EOF.
+func (b *liveBuffer) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// NewReader returns a reader positioned at the start of the stream.
+func (b *liveBuffer) NewReader() io.Reader {
+	return &liveReader{buf: b}
+}
+
+type liveReader struct {
+	buf *liveBuffer
+	off int
+}
+
+func (r *liveReader) Read(p []byte) (int, error) {
+	b := r.buf
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for r.off >= len(b.data) && !b.closed {
+		b.cond.Wait()
+	}
+	if r.off >= len(b.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, b.data[r.off:])
+	r.off += n
+	return n, nil
+}
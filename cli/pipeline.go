@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/toobprojects/go-commons/logs"
+)
+
+// Spec describes a single command invocation: the command, its arguments
+// and the options to run it with.
+type Spec struct {
+	Command string
+	Args    []string
+	Opts    Options
+}
+
+// Cmd is a shorthand for building a Spec with default options.
+func Cmd(command string, args ...string) Spec {
+	return Spec{Command: command, Args: args}
+}
+
+// Pipeline runs several commands connected like a shell pipe (a | b | c),
+// without going through a shell.
+type Pipeline struct {
+	stages []Spec
+}
+
+// Pipe builds a Pipeline from the given stages. Stdout of each stage is
+// connected to stdin of the next one.
+//
+// Per stage, Dir and Env are honoured. Stdin/Input are only used for the
+// first stage. Stderr of every stage is captured (and also streamed to the
+// stage's Options.Stderr when set); stdout of the last stage is captured
+// (and streamed to its Options.Stdout when set).
+func Pipe(stages ...Spec) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// PipelineResult holds the output of the last stage and a Result per stage.
+type PipelineResult struct {
+	// Stdout is the captured standard output of the last stage.
+	Stdout string
+
+	// Stages has one Result per stage, in order. Stage Stdout is empty
+	// (it was piped into the next stage); Stderr holds its captured stderr.
+	Stages []*Result
+
+	// Duration is the wall-clock time of the whole pipeline.
+	Duration time.Duration
+}
+
+// StageError reports the failure of one pipeline stage.
+type StageError struct {
+	Stage   int // zero-based index
+	Command string
+	Err     error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("pipeline stage %d (%s): %v", e.Stage, e.Command, e.Err)
+}
+
+func (e *StageError) Unwrap() error { return e.Err }
+
+// brokenPipe reports whether a non-final stage was killed by SIGPIPE, which
+// just means a later stage stopped reading early (e.g. `yes | head -1`).
+func brokenPipe(cmd *exec.Cmd, stage, stages int) bool {
+	if stage == stages-1 || cmd.ProcessState == nil {
+		return false
+	}
+	ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	return ok && ws.Signaled() && ws.Signal() == syscall.SIGPIPE
+}
+
+// withDefaults returns a copy of p with the package defaults (SetDefaults)
+// merged into every stage.
+func (p *Pipeline) withDefaults() *Pipeline {
+	stages := make([]Spec, len(p.stages))
+	for i, st := range p.stages {
+		st.Opts = withDefaults(st.Opts)
+		stages[i] = st
+	}
+	return &Pipeline{stages: stages}
+}
+
+// dryRun reports whether any stage (or the package) is in dry-run mode; a
+// pipeline is never partially executed.
+func (p *Pipeline) dryRun() bool {
+	for _, st := range p.stages {
+		if isDryRun(st.Opts) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run starts all stages, waits for them and returns the result. Like
+// `set -o pipefail`, the pipeline fails if any stage fails; the error joins
+// one *StageError per failed stage (use errors.As to inspect them).
+// Upstream stages terminated by SIGPIPE are not treated as failures.
+func (p *Pipeline) Run(ctx context.Context) (*PipelineResult, error) {
+	res := &PipelineResult{Stages: make([]*Result, len(p.stages))}
+	if len(p.stages) == 0 {
+		return res, errors.New("pipeline has no stages")
+	}
+
+	log := logs.WithGroup("cli")
+
+	p = p.withDefaults()
+	if p.dryRun() {
+		for i, st := range p.stages {
+			res.Stages[i] = dryRunResult(st.Command, st.Args, st.Opts)
+		}
+		return res, nil
+	}
+
+	cmds := make([]*exec.Cmd, len(p.stages))
+	stderrs := make([]bytes.Buffer, len(p.stages))
+	var stdout bytes.Buffer
+
+	// pipe ends that must be closed in the parent once the children have them
+	var parentEnds []*os.File
+	closeParentEnds := func() {
+		for _, f := range parentEnds {
+			_ = f.Close()
+		}
+		parentEnds = nil
+	}
+
+	for i, st := range p.stages {
+		opts := st.Opts
+		if i > 0 {
+			opts.Stdin, opts.Input = nil, ""
+		}
+		command, args := st.Command, st.Args
+		if opts.Remote != nil {
+			var err error
+			if command, args, err = opts.Remote.sshCommand(command, args, opts); err != nil {
+				closeParentEnds()
+				return res, &StageError{Stage: i, Command: st.Command, Err: err}
+			}
+			opts = opts.Remote.localOptions(opts)
+		}
+		cmd := newCmd(ctx, command, args, opts)
+		if err := setCredential(cmd, opts.Credential); err != nil {
+			closeParentEnds()
+			return res, &StageError{Stage: i, Command: st.Command, Err: err}
+		}
+
+		cmd.Stderr = &stderrs[i]
+		if w := outputWriter(opts.Stderr); w != nil {
+			cmd.Stderr = io.MultiWriter(&stderrs[i], w)
+		}
+
+		if i == len(p.stages)-1 {
+			cmd.Stdout = &stdout
+			if w := outputWriter(opts.Stdout); w != nil {
+				cmd.Stdout = io.MultiWriter(&stdout, w)
+			}
+		}
+
+		if i > 0 {
+			r, w, err := os.Pipe()
+			if err != nil {
+				closeParentEnds()
+				return res, fmt.Errorf("create pipe: %w", err)
+			}
+			cmds[i-1].Stdout = w
+			cmd.Stdin = r
+			parentEnds = append(parentEnds, r, w)
+		}
+		cmds[i] = cmd
+
+		if st.Opts.LogCommand {
+			log.Info("Running pipeline stage",
+				"stage", i,
+				"command", st.Command,
+				"args", redactArgs(st.Args, st.Opts),
+				"dir", st.Opts.Dir,
+			)
+		}
+	}
+
+	start := time.Now()
+	started := 0
+	var startErr error
+	for i, cmd := range cmds {
+		if err := cmd.Start(); err != nil {
+			startErr = &StageError{Stage: i, Command: p.stages[i].Command, Err: err}
+			break
+		}
+		started++
+	}
+	// The children hold their own copies now; close ours so EOF propagates.
+	closeParentEnds()
+
+	var errs []error
+	if startErr != nil {
+		errs = append(errs, startErr)
+		for _, cmd := range cmds[:started] {
+			_ = cmd.Process.Kill()
+		}
+	}
+
+	for i := range cmds {
+		r := &Result{Stderr: stderrs[i].String(), ExitCode: -1}
+		if i < started {
+			err := cmds[i].Wait()
+			r.Stderr = stderrs[i].String()
+			r.ExitCode = exitCode(cmds[i], err)
+			r.Pid = cmds[i].Process.Pid
+			r.Duration = time.Since(start)
+			if err != nil && startErr == nil && !brokenPipe(cmds[i], i, len(cmds)) {
+				errs = append(errs, &StageError{Stage: i, Command: p.stages[i].Command, Err: newExitError(err, r)})
+			}
+		}
+		res.Stages[i] = r
+	}
+
+	res.Stdout = stdout.String()
+	res.Duration = time.Since(start)
+
+	if err := errors.Join(errs...); err != nil {
+		log.Error("Pipeline failed", "stages", len(cmds), "err", err)
+		return res, err
+	}
+	return res, nil
+}
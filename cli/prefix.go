@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"hash/fnv"
+	"io"
+)
+
+// prefixColors is the palette Options.PrefixColor picks from, like
+// docker-compose does for its services.
+var prefixColors = []string{
+	"\033[36m", // cyan
+	"\033[33m", // yellow
+	"\033[32m", // green
+	"\033[35m", // magenta
+	"\033[34m", // blue
+	"\033[96m", // bright cyan
+	"\033[93m", // bright yellow
+	"\033[92m", // bright green
+	"\033[95m", // bright magenta
+	"\033[94m", // bright blue
+}
+
+const prefixColorReset = "\033[0m"
+
+// linePrefix renders the "[label] " prefix, colored by a hash of the label
+// so each label keeps the same color across runs.
+func linePrefix(label string, color bool) string {
+	p := "[" + label + "] "
+	if !color {
+		return p
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(label))
+	return prefixColors[h.Sum32()%uint32(len(prefixColors))] + p + prefixColorReset
+}
+
+// newPrefixWriter returns a lineWriter writing every line to dst with
+// prefix in front. Each line goes out in a single Write so lines from
+// concurrent commands sharing dst don't interleave.
+func newPrefixWriter(dst io.Writer, prefix string) *lineWriter {
+	return newLineWriter(func(line string) {
+		_, _ = io.WriteString(dst, prefix+line+"\n")
+	})
+}
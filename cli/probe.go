@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// versionTimeout bounds how long Version waits for `<tool> --version`.
+const versionTimeout = 10 * time.Second
+
+// ErrCommandNotFound is wrapped by RequireCommands for every missing command.
+var ErrCommandNotFound = errors.New("command not found")
+
+// CommandExists reports whether name resolves to an executable, either as a
+// path or via PATH lookup.
+func CommandExists(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// RequireCommands checks that every named command is available. It returns
+// nil if all exist, otherwise one error listing every missing command (each
+// wrapped with ErrCommandNotFound, so errors.Is works).
+func RequireCommands(names ...string) error {
+	var errs []error
+	for _, name := range names {
+		if !CommandExists(name) {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrCommandNotFound, name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Version runs `name versionArgs...` (default: --version) and returns the
+// first non-empty line of its output (stdout and stderr), trimmed, e.g.
+// "git version 2.43.0".
+//
+// The probe runs directly, not through Run: SetDefaults, Use middleware and
+// dry-run mode don't apply, so it reports the real tool even in a dry run.
+func Version(name string, versionArgs ...string) (string, error) {
+	if len(versionArgs) == 0 {
+		versionArgs = []string{"--version"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, versionArgs...)
+	cmd.WaitDelay = timeoutWaitDelay
+	b, err := cmd.CombinedOutput()
+	out := string(b)
+	if ctx.Err() != nil {
+		err = ErrTimeout
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s version: %w", name, err)
+	}
+
+	for line := range strings.SplitSeq(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("%s version: empty output", name)
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+)
+
+// Process is a handle to a command started with Start.
+type Process struct {
+	e      *execution
+	stdout *liveBuffer
+	stderr *liveBuffer
+	done   chan struct{}
+	res    *Result
+	err    error
+}
+
+// Start launches a command in the background and returns immediately.
+// All Options apply (Timeout, ProcessGroup, PTY, line callbacks, ...);
+// Retries are ignored. The process is killed when ctx is cancelled.
+//
+// Output is additionally recorded so it can be followed live through
+// Stdout / Stderr readers. It is kept in memory for the lifetime of the
+// Process, so prefer streaming destinations for very chatty commands.
+func Start(ctx context.Context, command string, args []string, opts Options) (*Process, error) {
+	opts = withDefaults(opts)
+	if opts.LogCommand {
+		logCommand(command, args, opts)
+	}
+
+	p := &Process{
+		stdout: newLiveBuffer(),
+		stderr: newLiveBuffer(),
+		done:   make(chan struct{}),
+	}
+
+	e, err := newExecution(ctx, command, args, opts, p.stdout, p.stderr)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.begin(); err != nil {
+		_, err = e.finish(err)
+		return nil, err
+	}
+	p.e = e
+
+	go p.reap()
+	return p, nil
+}
+
+// reap waits for the process in the background so Done works without Wait.
+func (p *Process) reap() {
+	p.res, p.err = p.e.wait()
+	p.stdout.Close()
+	p.stderr.Close()
+	close(p.done)
+}
+
+// Pid returns the process id.
+func (p *Process) Pid() int {
+	return p.e.cmd.Process.Pid
+}
+
+// Wait blocks until the process exits and returns its Result. It may be
+// called several times and from several goroutines.
+func (p *Process) Wait() (*Result, error) {
+	<-p.done
+	return p.res, p.err
+}
+
+// Done is closed when the process has exited.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Kill terminates the process immediately (the whole group with
+// Options.ProcessGroup).
+func (p *Process) Kill() error {
+	return p.Signal(os.Kill)
+}
+
+// Signal sends sig to the process (to the whole group with
+// Options.ProcessGroup). On Windows only os.Kill is supported.
+func (p *Process) Signal(sig os.Signal) error {
+	select {
+	case <-p.done:
+		return os.ErrProcessDone
+	default:
+	}
+
+	var err error
+	if p.e.pg != nil {
+		err = p.e.pg.signal(sig)
+	} else {
+		err = p.e.cmd.Process.Signal(sig)
+	}
+	if errors.Is(err, os.ErrProcessDone) {
+		return os.ErrProcessDone
+	}
+	return err
+}
+
+// Stdout returns a reader that follows the command's standard output from
+// the beginning. Standard error always goes to Stderr, whatever
+// Options.SeparateStderr says, except under a PTY, where the terminal
+// merges both streams into Stdout. It returns io.EOF once the process has exited and all
+// output has been read. Each call returns an independent reader.
+func (p *Process) Stdout() io.Reader {
+	return p.stdout.NewReader()
+}
+
+// Stderr returns a reader that follows the command's standard error, like Stdout.
+func (p *Process) Stderr() io.Reader {
+	return p.stderr.NewReader()
+}
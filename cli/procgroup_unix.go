@@ -0,0 +1,50 @@
+//go:build unix
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// processGroup puts the command in its own process group and makes context
+// cancellation (and timeouts) kill the whole group, including grandchildren
+// spawned by shells.
+type processGroup struct {
+	cmd *exec.Cmd
+}
+
+func newProcessGroup(cmd *exec.Cmd) *processGroup {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// A new session (used for PTYs) already is its own process group, and
+	// setpgid would fail on a session leader.
+	if !cmd.SysProcAttr.Setsid {
+		cmd.SysProcAttr.Setpgid = true
+	}
+
+	pg := &processGroup{cmd: cmd}
+	cmd.Cancel = func() error { return pg.signal(syscall.SIGKILL) }
+	return pg
+}
+
+// afterStart is a no-op on unix: the group exists from the moment of fork.
+func (pg *processGroup) afterStart() error { return nil }
+
+// signal sends sig to every process in the group.
+func (pg *processGroup) signal(sig os.Signal) error {
+	if pg.cmd.Process == nil {
+		return nil
+	}
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return fmt.Errorf("unsupported signal %v", sig)
+	}
+	// A negative pid addresses the process group (pgid == leader pid).
+	return syscall.Kill(-pg.cmd.Process.Pid, s)
+}
+
+func (pg *processGroup) close() {}
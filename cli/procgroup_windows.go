@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+var (
+	modkernel32                  = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObjectW         = modkernel32.NewProc("CreateJobObjectW")
+	procAssignProcessToJobObject = modkernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = modkernel32.NewProc("TerminateJobObject")
+)
+
+const (
+	createNewProcessGroup = 0x00000200
+	processSetQuota       = 0x0100
+	processTerminate      = 0x0001
+)
+
+// processGroup assigns the command to a Job Object so that cancellation
+// (and timeouts) terminate the whole process tree.
+//
+// The process is assigned right after it starts; children it spawns
+// before that moment are not part of the job.
+type processGroup struct {
+	cmd *exec.Cmd
+	job syscall.Handle
+}
+
+func newProcessGroup(cmd *exec.Cmd) *processGroup {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= createNewProcessGroup
+
+	pg := &processGroup{cmd: cmd}
+	cmd.Cancel = pg.terminate
+	return pg
+}
+
+// terminate kills every process in the job (or just the leader if the job
+// could not be set up).
+func (pg *processGroup) terminate() error {
+	if pg.job != 0 {
+		r, _, err := procTerminateJobObject.Call(uintptr(pg.job), 1)
+		if r == 0 {
+			return err
+		}
+		return nil
+	}
+	return pg.cmd.Process.Kill()
+}
+
+// signal only supports os.Kill on Windows, which terminates the whole job.
+func (pg *processGroup) signal(sig os.Signal) error {
+	if sig != os.Kill {
+		return fmt.Errorf("unsupported signal %v on windows", sig)
+	}
+	return pg.terminate()
+}
+
+func (pg *processGroup) afterStart() error {
+	job, _, err := procCreateJobObjectW.Call(0, 0)
+	if job == 0 {
+		return err
+	}
+
+	ph, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(pg.cmd.Process.Pid))
+	if err != nil {
+		_ = syscall.CloseHandle(syscall.Handle(job))
+		return err
+	}
+	defer func() { _ = syscall.CloseHandle(ph) }()
+
+	r, _, err := procAssignProcessToJobObject.Call(job, uintptr(ph))
+	if r == 0 {
+		_ = syscall.CloseHandle(syscall.Handle(job))
+		return err
+	}
+	pg.job = syscall.Handle(job)
+	return nil
+}
+
+func (pg *processGroup) close() {
+	if pg.job != 0 {
+		_ = syscall.CloseHandle(pg.job)
+		pg.job = 0
+	}
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrPTYUnsupported is returned when Options.PTY is used on a platform
+// without pseudo-terminal support (currently anything but Linux and macOS).
+var ErrPTYUnsupported = errors.New("pty is not supported on this platform")
+
+// WindowSize is the terminal size reported to a command running under a PTY.
+type WindowSize struct {
+	Rows uint16
+	Cols uint16
+}
+
+// DefaultWindowSize is used when Options.PTYSize is nil.
+var DefaultWindowSize = WindowSize{Rows: 24, Cols: 80}
+
+// defaultTerm is set as TERM for PTY commands when neither the parent nor
+// Options.Env defines one, so tools enable colors and progress bars.
+const defaultTerm = "xterm-256color"
+
+// eot is the terminal end-of-transmission character (Ctrl-D).
+const eot = 0x04
+
+// ptySession connects a command to a pseudo-terminal and pumps its output.
+type ptySession struct {
+	master, slave *os.File
+	in            io.Reader
+	out           io.Writer
+	copyDone      chan struct{}
+}
+
+// newPTY allocates a PTY and attaches it to cmd. out receives everything the
+// command writes (stdout and stderr are merged by the terminal). Must be
+// called after the output writers have been wired and before cmd.Start.
+func newPTY(cmd *exec.Cmd, opts Options, out io.Writer) (*ptySession, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+
+	size := DefaultWindowSize
+	if opts.PTYSize != nil {
+		size = *opts.PTYSize
+	}
+	if err := setWindowSize(master, size); err != nil {
+		_ = master.Close()
+		_ = slave.Close()
+		return nil, err
+	}
+
+	p := &ptySession{
+		master:   master,
+		slave:    slave,
+		in:       cmd.Stdin,
+		out:      out,
+		copyDone: make(chan struct{}),
+	}
+	attachPTY(cmd, slave)
+
+	if os.Getenv("TERM") == "" && !hasEnvKey(opts.Env, "TERM") {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(slices.Clip(cmd.Env), "TERM="+defaultTerm)
+	}
+	return p, nil
+}
+
+// afterStart releases the parent's copy of the slave and starts pumping.
+func (p *ptySession) afterStart() {
+	_ = p.slave.Close()
+
+	go func() {
+		defer close(p.copyDone)
+		// Reading the master fails with EIO once the last slave fd is closed;
+		// that is the normal end of output, not an error.
+		_, _ = io.Copy(p.out, p.master)
+	}()
+
+	if p.in != nil {
+		go func() {
+			_, _ = io.Copy(p.master, p.in)
+			// A terminal has no "close stdin"; send EOT (Ctrl-D) so readers see EOF.
+			_, _ = p.master.Write([]byte{eot})
+		}()
+	}
+}
+
+// finish waits (bounded) for the remaining output and closes the master.
+func (p *ptySession) finish() {
+	select {
+	case <-p.copyDone:
+	case <-time.After(timeoutWaitDelay):
+		// Orphaned grandchildren still hold the terminal; stop reading.
+	}
+	_ = p.master.Close()
+}
+
+// abort releases both ends when the command never started.
+func (p *ptySession) abort() {
+	_ = p.slave.Close()
+	_ = p.master.Close()
+}
+
+func hasEnvKey(env []string, key string) bool {
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			return true
+		}
+	}
+	return false
+}
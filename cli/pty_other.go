@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+)
+
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, ErrPTYUnsupported
+}
+
+func setWindowSize(*os.File, WindowSize) error {
+	return ErrPTYUnsupported
+}
+
+func attachPTY(*exec.Cmd, *os.File) {}
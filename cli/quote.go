@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Quote quotes arg for the current platform's shell: QuoteWindows on
+// Windows, QuotePOSIX elsewhere. Arguments that need no quoting are returned
+// unchanged.
+func Quote(arg string) string {
+	if runtime.GOOS == "windows" {
+		return QuoteWindows(arg)
+	}
+	return QuotePOSIX(arg)
+}
+
+// QuoteAll quotes each argument with Quote and joins them with spaces,
+// producing a command line that can be pasted into a shell or passed to
+// `bash -c`.
+func QuoteAll(args []string) string {
+	return joinQuoted(args, Quote)
+}
+
+// QuotePOSIX quotes arg for sh/bash/zsh using single quotes, so spaces, $,
+// backticks, globs and the like are taken literally.
+func QuotePOSIX(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if strings.IndexFunc(arg, func(r rune) bool { return !posixSafe(r) }) < 0 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// QuoteAllPOSIX is QuoteAll using QuotePOSIX.
+func QuoteAllPOSIX(args []string) string {
+	return joinQuoted(args, QuotePOSIX)
+}
+
+// QuoteWindows quotes arg so the standard Windows command-line parser
+// (CommandLineToArgvW, used by Go and the C runtime) reads it back as a
+// single argument: double quotes, with backslashes escaped only where they
+// precede a quote.
+func QuoteWindows(arg string) string {
+	if arg == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(arg, " \t\n\v\"") {
+		return arg
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	slashes := 0
+	for i := 0; i < len(arg); i++ {
+		c := arg[i]
+		switch c {
+		case '\\':
+			slashes++
+		case '"':
+			// Double the pending backslashes, then escape the quote.
+			b.WriteString(strings.Repeat(`\`, slashes*2+1))
+			slashes = 0
+		default:
+			b.WriteString(strings.Repeat(`\`, slashes))
+			slashes = 0
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+		}
+	}
+	// Backslashes before the closing quote must be doubled as well.
+	b.WriteString(strings.Repeat(`\`, slashes*2))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// QuoteAllWindows is QuoteAll using QuoteWindows.
+func QuoteAllWindows(args []string) string {
+	return joinQuoted(args, QuoteWindows)
+}
+
+func joinQuoted(args []string, quote func(string) string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// posixSafe reports whether r never needs quoting in a POSIX shell.
+func posixSafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("@%+=:,./_-", r)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+)
+
+// redacted replaces secret values in logged command lines.
+const redacted = "[REDACTED]"
+
+// redactArgs returns args as they should be logged: values of
+// Options.RedactFlags and matches of Options.RedactPatterns are masked.
+// args itself is never modified; the command still gets the real values.
+func redactArgs(args []string, opts Options) []string {
+	if len(opts.RedactFlags) == 0 && len(opts.RedactPatterns) == 0 {
+		return args
+	}
+
+	out := make([]string, len(args))
+	maskNext := false
+	for i, a := range args {
+		switch {
+		case maskNext:
+			out[i] = redacted
+			maskNext = false
+			continue
+		case isRedactFlag(a, opts.RedactFlags):
+			// "--password secret": the value is the next argument.
+			out[i] = a
+			maskNext = true
+			continue
+		}
+
+		if name, _, ok := strings.Cut(a, "="); ok && isRedactFlag(name, opts.RedactFlags) {
+			out[i] = name + "=" + redacted
+			continue
+		}
+		out[i] = redactPatterns(a, opts.RedactPatterns)
+	}
+	return out
+}
+
+// redactEnv masks the values of KEY=VALUE pairs whose key is a redacted
+// flag name (e.g. "GITHUB_TOKEN") or that match a redaction pattern.
+func redactEnv(env []string, opts Options) []string {
+	if len(opts.RedactFlags) == 0 && len(opts.RedactPatterns) == 0 {
+		return env
+	}
+
+	out := make([]string, len(env))
+	for i, kv := range env {
+		if key, _, ok := strings.Cut(kv, "="); ok && isRedactKey(key, opts.RedactFlags) {
+			out[i] = key + "=" + redacted
+			continue
+		}
+		out[i] = redactPatterns(kv, opts.RedactPatterns)
+	}
+	return out
+}
+
+// isRedactFlag matches a flag argument against flag names, with or without
+// dashes: "password" matches "-password" and "--password". Positional
+// arguments never match.
+func isRedactFlag(arg string, flags []string) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+	return isRedactKey(strings.TrimLeft(arg, "-"), flags)
+}
+
+// isRedactKey matches a bare name (flag without dashes, or env key).
+func isRedactKey(name string, flags []string) bool {
+	if name == "" {
+		return false
+	}
+	for _, f := range flags {
+		if name == strings.TrimLeft(f, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+func redactPatterns(s string, patterns []*regexp.Regexp) string {
+	for _, re := range patterns {
+		s = re.ReplaceAllString(s, redacted)
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidRemote is returned when Remote.Host or Remote.User can't be
+// passed to ssh safely.
+var ErrInvalidRemote = errors.New("invalid remote")
+
+// Remote runs commands on another host through the system OpenSSH client
+// (ssh must be on PATH). Set Options.Remote and the same Run / RunResult /
+// Start calls execute remotely and return the same Result; the exit code is
+// the remote command's, or 255 when ssh itself fails.
+//
+// Authentication is non-interactive (BatchMode): keys from IdentityFile,
+// the ssh agent (SSH_AUTH_SOCK, kept even with Options.CleanEnv) or
+// ~/.ssh/config. The remote login shell must be POSIX-compatible.
+type Remote struct {
+	// Host and User may not start with "-" or contain whitespace, so they
+	// can't be mistaken for ssh options.
+	Host string
+	User string // default: ssh's default (current user or ~/.ssh/config)
+	Port int    // default: 22 or ~/.ssh/config
+
+	// IdentityFile is a private key to authenticate with. When set, only
+	// this key is offered.
+	IdentityFile string
+
+	// KnownHostsFile replaces ~/.ssh/known_hosts for host key verification.
+	KnownHostsFile string
+
+	// HostKeyCheck sets StrictHostKeyChecking: "yes" (default; unknown hosts
+	// fail), "accept-new" (trust on first use) or "no" (never verify — only
+	// for throwaway test hosts).
+	HostKeyCheck string
+
+	// ConnectTimeout bounds connection setup (default: ssh's).
+	ConnectTimeout time.Duration
+
+	// ExtraArgs are passed to ssh before the destination, e.g.
+	// []string{"-o", "ProxyJump=bastion"}.
+	ExtraArgs []string
+}
+
+// sshCommand rewrites command for execution on r. Options.Dir and
+// Options.Env are applied on the remote side; run the result with
+// localOptions(opts).
+func (r *Remote) sshCommand(command string, args []string, opts Options) (string, []string, error) {
+	if err := r.validate(); err != nil {
+		return "", nil, err
+	}
+
+	sshArgs := []string{"-o", "BatchMode=yes"}
+	if r.Port > 0 {
+		sshArgs = append(sshArgs, "-p", strconv.Itoa(r.Port))
+	}
+	if r.IdentityFile != "" {
+		sshArgs = append(sshArgs, "-i", r.IdentityFile, "-o", "IdentitiesOnly=yes")
+	}
+	if r.KnownHostsFile != "" {
+		sshArgs = append(sshArgs, "-o", "UserKnownHostsFile="+r.KnownHostsFile)
+	}
+	if r.HostKeyCheck != "" {
+		sshArgs = append(sshArgs, "-o", "StrictHostKeyChecking="+r.HostKeyCheck)
+	}
+	if r.ConnectTimeout > 0 {
+		secs := max(int(r.ConnectTimeout/time.Second), 1)
+		sshArgs = append(sshArgs, "-o", "ConnectTimeout="+strconv.Itoa(secs))
+	}
+	if opts.PTY {
+		sshArgs = append(sshArgs, "-tt")
+	} else {
+		sshArgs = append(sshArgs, "-T")
+	}
+	sshArgs = append(sshArgs, r.ExtraArgs...)
+
+	dest := r.Host
+	if r.User != "" {
+		dest = r.User + "@" + r.Host
+	}
+	sshArgs = append(sshArgs, dest, "--", remoteCommandLine(command, args, opts))
+	return "ssh", sshArgs, nil
+}
+
+// validate rejects a Host or User that ssh would parse as an option (a
+// leading "-") or that would split into several words.
+func (r *Remote) validate() error {
+	if r.Host == "" {
+		return fmt.Errorf("%w: empty host", ErrInvalidRemote)
+	}
+	for _, f := range []struct{ name, value string }{{"host", r.Host}, {"user", r.User}} {
+		if strings.HasPrefix(f.value, "-") || strings.ContainsFunc(f.value, unicode.IsSpace) {
+			return fmt.Errorf("%w: %s %q", ErrInvalidRemote, f.name, f.value)
+		}
+	}
+	return nil
+}
+
+// localOptions returns the Options for the local ssh process: Dir and Env
+// already went into the remote command line, and the agent socket survives
+// CleanEnv / EnvAllowlist so the agent can still authenticate.
+func (r *Remote) localOptions(opts Options) Options {
+	opts.Dir, opts.Env = "", nil
+	if opts.CleanEnv || len(opts.EnvAllowlist) > 0 {
+		opts.EnvAllowlist = append(slices.Clip(opts.EnvAllowlist), "SSH_AUTH_SOCK")
+	}
+	return opts
+}
+
+// remoteCommandLine renders the POSIX command line run by the remote shell.
+func remoteCommandLine(command string, args []string, opts Options) string {
+	var b strings.Builder
+	if opts.Dir != "" {
+		b.WriteString("cd " + QuotePOSIX(opts.Dir) + " && ")
+	}
+	if len(opts.Env) > 0 {
+		b.WriteString("env " + QuoteAllPOSIX(opts.Env) + " ")
+	}
+	b.WriteString(QuoteAllPOSIX(append([]string{command}, args...)))
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Result describes a finished command.
+type Result struct {
+	// Stdout holds the captured output. With CaptureOutput it contains the
+	// combined stdout+stderr stream, or stdout only with SeparateStderr;
+	// when streaming it is empty.
+	Stdout string
+
+	// Stderr holds the captured standard error when SeparateStderr is set.
+	Stderr string
+
+	// ExitCode is the process exit code: 0 on success, -1 when the command
+	// could not be started or was terminated by a signal.
+	ExitCode int
+
+	// Duration is the wall-clock time from start to exit.
+	Duration time.Duration
+
+	// Pid is the process id of the command, or 0 if it never started.
+	Pid int
+
+	// Attempts is how many times the command was run (1 without retries,
+	// 0 in dry-run mode).
+	Attempts int
+
+	// Truncated is true when captured output exceeded
+	// Options.MaxOutputBytes and part of it was discarded.
+	Truncated bool
+
+	// Cached is true when the Result was served from the Options.CacheTTL
+	// cache instead of running the command.
+	Cached bool
+
+	// DryRun is true when the command was only logged, not executed.
+	DryRun bool
+}
+
+// Success reports whether the command exited with code 0.
+func (r *Result) Success() bool {
+	return r != nil && r.ExitCode == 0
+}
+
+// exitCode derives the exit code from the finished command and its error.
+func exitCode(cmd *exec.Cmd, err error) int {
+	if cmd.ProcessState != nil {
+		return cmd.ProcessState.ExitCode()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+// ExitError is returned (possibly wrapped, e.g. with ErrTimeout) when a
+// command ran but did not exit successfully. Use errors.As to branch on the
+// exit code without matching error strings:
+//
+//	var exit *cli.ExitError
+//	if errors.As(err, &exit) && exit.Code == 2 { ... }
+//
+// It wraps the underlying *exec.ExitError.
+type ExitError struct {
+	// Code is the exit code, or -1 when the process was killed by a signal.
+	Code int
+
+	// Signal is the signal that terminated the process, or nil.
+	Signal os.Signal
+
+	// Stderr is the captured standard error (with SeparateStderr), or the
+	// combined captured output otherwise. Empty when output was streamed.
+	Stderr string
+
+	Err error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// newExitError converts an *exec.ExitError from cmd into an *ExitError
+// carrying res's output; other errors are returned unchanged.
+func newExitError(err error, res *Result) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	e := &ExitError{Code: exitErr.ExitCode(), Stderr: res.Stderr, Err: err}
+	if e.Stderr == "" {
+		e.Stderr = res.Stdout
+	}
+	if ws, ok := exitErr.Sys().(interface {
+		Signaled() bool
+		Signal() syscall.Signal
+	}); ok && ws.Signaled() {
+		e.Signal = ws.Signal()
+	}
+	return e
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/toobprojects/go-commons/logs"
+)
+
+// Backoff describes an exponential backoff with jitter.
+type Backoff struct {
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+
+	// Max caps the delay between retries, before jitter is applied (so a
+	// capped delay can still vary by ±Jitter).
+	Max time.Duration
+
+	// Multiplier grows the delay after every retry (e.g. 2 doubles it).
+	Multiplier float64
+
+	// Jitter randomizes each delay by ±Jitter (0.2 = ±20%) so that many
+	// clients don't retry in lockstep.
+	Jitter float64
+}
+
+// DefaultBackoff supplies Initial, Max and Multiplier when they are zero in
+// Options.Backoff. It has no Jitter: a zero Jitter means no jitter, so
+// there is nothing to default.
+var DefaultBackoff = Backoff{
+	Initial:    500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+}
+
+// Delay returns the wait before retry number attempt (1-based).
+func (b Backoff) Delay(attempt int) time.Duration {
+	b = b.withDefaults()
+
+	d := float64(b.Initial)
+	for i := 1; i < attempt; i++ {
+		d *= b.Multiplier
+		if d >= float64(b.Max) {
+			d = float64(b.Max)
+			break
+		}
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(d)
+}
+
+func (b Backoff) withDefaults() Backoff {
+	if b.Initial <= 0 {
+		b.Initial = DefaultBackoff.Initial
+	}
+	if b.Max <= 0 {
+		b.Max = DefaultBackoff.Max
+	}
+	if b.Multiplier <= 0 {
+		b.Multiplier = DefaultBackoff.Multiplier
+	}
+	if b.Jitter < 0 {
+		b.Jitter = 0
+	}
+	return b
+}
+
+// RunWithRetry is RunResult with retries enabled; it re-runs the command up
+// to retries times using opts.Backoff.
+func RunWithRetry(ctx context.Context, command string, args []string, retries int, opts Options) (*Result, error) {
+	opts.Retries = retries
+	return RunResult(ctx, command, args, opts)
+}
+
+func runWithRetry(ctx context.Context, command string, args []string, opts Options) (*Result, error) {
+	log := logs.WithGroup("cli").With("command", command)
+
+	var (
+		res *Result
+		err error
+	)
+	for attempt := 0; ; attempt++ {
+		res, err = runOnce(ctx, command, args, opts)
+		res.Attempts = attempt + 1
+
+		if err == nil || attempt >= opts.Retries || !retryable(res, opts) {
+			return res, err
+		}
+
+		delay := opts.Backoff.Delay(attempt + 1)
+		log.Warn("Command failed, retrying",
+			"attempt", attempt+1,
+			"of", opts.Retries+1,
+			"exit_code", res.ExitCode,
+			"delay", delay,
+			"err", err,
+		)
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return res, err
+		case <-t.C:
+		}
+	}
+}
+
+func retryable(res *Result, opts Options) bool {
+	if len(opts.RetryOnExitCodes) == 0 {
+		return true
+	}
+	return slices.Contains(opts.RetryOnExitCodes, res.ExitCode)
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ScriptOptions controls how a script is executed.
+type ScriptOptions struct {
+	// Options are the regular run options (Dir, Env, CaptureOutput, ...).
+	Options
+
+	// Shell overrides the interpreter (name or path), e.g. "zsh" or "pwsh".
+	// If empty, DetectShell picks one for the platform and script type.
+	Shell string
+
+	// ShellArgs are passed to the interpreter before the script path,
+	// e.g. []string{"-e", "-u"} for bash. If nil and Shell is empty, the
+	// detected defaults are used.
+	ShellArgs []string
+
+	// Args are passed to the script itself, after the script path.
+	Args []string
+}
+
+// DetectShell returns the interpreter and its default arguments for running
+// scriptPath on the current platform:
+//
+//	Unix:    bash if available, otherwise sh
+//	Windows: .ps1 => pwsh/powershell -NoProfile -ExecutionPolicy Bypass -File
+//	         .bat/.cmd => cmd /C
+//	         anything else => pwsh/powershell -NoProfile -ExecutionPolicy Bypass -File
+func DetectShell(scriptPath string) (string, []string) {
+	if runtime.GOOS != "windows" {
+		if p, err := exec.LookPath("bash"); err == nil {
+			return p, nil
+		}
+		return "/bin/sh", nil
+	}
+
+	switch strings.ToLower(filepath.Ext(scriptPath)) {
+	case ".bat", ".cmd":
+		return "cmd", []string{"/C"}
+	default:
+		return powershell(), []string{"-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File"}
+	}
+}
+
+// powershell prefers PowerShell 7+ (pwsh) and falls back to Windows PowerShell.
+func powershell() string {
+	if p, err := exec.LookPath("pwsh"); err == nil {
+		return p
+	}
+	return "powershell"
+}
+
+// RunScriptFile executes the script at scriptPath with a detected (or
+// overridden) interpreter and returns the full Result.
+func RunScriptFile(ctx context.Context, scriptPath string, opts ScriptOptions) (*Result, error) {
+	shell, shellArgs := opts.Shell, opts.ShellArgs
+	if shell == "" {
+		detected, defaults := DetectShell(scriptPath)
+		shell = detected
+		if shellArgs == nil {
+			shellArgs = defaults
+		}
+	}
+
+	args := make([]string, 0, len(shellArgs)+1+len(opts.Args))
+	args = append(args, shellArgs...)
+	args = append(args, scriptPath)
+	args = append(args, opts.Args...)
+
+	return RunResult(ctx, shell, args, opts.Options)
+}
+
+// ExecScript runs an inline script body: it is written to a private temp
+// file (mode 0600), run like RunScriptFile and removed afterwards. The
+// file extension follows the interpreter (.sh, .ps1 or .cmd), which matters
+// on Windows.
+func ExecScript(ctx context.Context, script string, opts ScriptOptions) (*Result, error) {
+	f, err := os.CreateTemp("", "go-commons-script-*"+scriptExt(opts.Shell))
+	if err != nil {
+		return &Result{ExitCode: -1}, fmt.Errorf("create script file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	_, err = f.WriteString(script)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return &Result{ExitCode: -1}, fmt.Errorf("write script file %q: %w", path, err)
+	}
+
+	return RunScriptFile(ctx, path, opts)
+}
+
+// scriptExt picks the temp file extension for ExecScript from the shell.
+func scriptExt(shell string) string {
+	name := shellName(shell)
+	switch {
+	case name == "cmd":
+		return ".cmd"
+	case name == "pwsh" || name == "powershell":
+		return ".ps1"
+	case shell == "" && runtime.GOOS == "windows":
+		return ".ps1"
+	default:
+		return ".sh"
+	}
+}
+
+// ShellCommand returns the interpreter and arguments that run the command
+// line through the platform's shell:
+//
+//	Unix:    /bin/sh -c line
+//	Windows: pwsh/powershell -NoProfile -NonInteractive -Command line
+func ShellCommand(line string) (string, []string) {
+	if runtime.GOOS == "windows" {
+		return powershell(), append(shellFlags("powershell"), line)
+	}
+	return "/bin/sh", []string{"-c", line}
+}
+
+// RunShell runs a command line through a shell, so pipes, redirections and
+// variables work, with the same code on Unix and Windows agents (see
+// ShellCommand). opts.Shell selects another shell (e.g. "bash", "cmd");
+// the matching flag (-c, /C or -Command) is added unless ShellArgs is set.
+// For POSIX shells opts.Args become $1, $2, ...
+func RunShell(ctx context.Context, line string, opts ScriptOptions) (*Result, error) {
+	shell, args := ShellCommand(line)
+	if opts.Shell != "" {
+		shell = opts.Shell
+		flags := opts.ShellArgs
+		if flags == nil {
+			flags = shellFlags(shell)
+		}
+		args = append(slices.Clip(flags), line)
+	}
+
+	if len(opts.Args) > 0 {
+		if !isWindowsShell(shell) {
+			// sh -c 'cmd' $0 $1 ...
+			args = append(args, filepath.Base(shell))
+		}
+		args = append(args, opts.Args...)
+	}
+	return RunResult(ctx, shell, args, opts.Options)
+}
+
+// shellFlags returns the flags that make shell execute a command string.
+func shellFlags(shell string) []string {
+	switch shellName(shell) {
+	case "cmd":
+		return []string{"/C"}
+	case "pwsh", "powershell":
+		return []string{"-NoProfile", "-NonInteractive", "-Command"}
+	default:
+		return []string{"-c"}
+	}
+}
+
+func isWindowsShell(shell string) bool {
+	switch shellName(shell) {
+	case "cmd", "pwsh", "powershell":
+		return true
+	}
+	return false
+}
+
+// shellName normalizes a shell path to its lower-case base name without
+// ".exe".
+func shellName(shell string) string {
+	return strings.TrimSuffix(strings.ToLower(filepath.Base(shell)), ".exe")
+}
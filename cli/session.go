@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/toobprojects/go-commons/logs"
+)
+
+// ErrDirStackEmpty is returned by Session.Popd when there is nothing to pop.
+var ErrDirStackEmpty = errors.New("directory stack empty")
+
+// Pushd changes the process working directory to dir and returns popd,
+// which changes it back (a failure to do so is logged), so it can be
+// deferred. Like its shell namesake it affects the whole
+// process, so it is not safe to use from concurrent goroutines; prefer a
+// Session (or Options.Dir) in concurrent code.
+func Pushd(dir string) (popd func(), err error) {
+	prev, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("pushd %q: %w", dir, err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, fmt.Errorf("pushd %q: %w", dir, err)
+	}
+	return func() {
+		if err := os.Chdir(prev); err != nil {
+			logs.WithGroup("cli").Warn("popd failed", "dir", prev, "err", err)
+		}
+	}, nil
+}
+
+// Session runs commands against a directory stack, like a shell script
+// using pushd/popd, without touching the process working directory. It is
+// safe for concurrent use.
+type Session struct {
+	mu   sync.Mutex
+	dirs []string
+}
+
+// NewSession returns a Session whose current directory is the process
+// working directory until something is pushed.
+func NewSession() *Session {
+	return &Session{}
+}
+
+// Pushd makes dir (resolved against the current session directory) the new
+// current directory. It must be an existing directory.
+func (s *Session) Pushd(dir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	abs, err := filepath.Abs(s.resolve(dir))
+	if err != nil {
+		return fmt.Errorf("pushd %q: %w", dir, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return fmt.Errorf("pushd %q: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("pushd %q: not a directory", dir)
+	}
+	s.dirs = append(s.dirs, abs)
+	return nil
+}
+
+// Popd returns to the previous directory.
+func (s *Session) Popd() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.dirs) == 0 {
+		return ErrDirStackEmpty
+	}
+	s.dirs = s.dirs[:len(s.dirs)-1]
+	return nil
+}
+
+// Dir returns the current session directory ("" means the process working
+// directory).
+func (s *Session) Dir() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolve("")
+}
+
+// Stack returns the directory stack, innermost last.
+func (s *Session) Stack() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.dirs...)
+}
+
+// Run is Run in the current session directory. A relative opts.Dir is
+// resolved against it.
+func (s *Session) Run(ctx context.Context, command string, args []string, opts Options) (string, error) {
+	opts.Dir = s.dir(opts.Dir)
+	return Run(ctx, command, args, opts)
+}
+
+// RunResult is RunResult in the current session directory. A relative
+// opts.Dir is resolved against it.
+func (s *Session) RunResult(ctx context.Context, command string, args []string, opts Options) (*Result, error) {
+	opts.Dir = s.dir(opts.Dir)
+	return RunResult(ctx, command, args, opts)
+}
+
+func (s *Session) dir(dir string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolve(dir)
+}
+
+// resolve joins a relative dir onto the top of the stack. Callers hold mu.
+func (s *Session) resolve(dir string) string {
+	if len(s.dirs) == 0 || filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(s.dirs[len(s.dirs)-1], dir)
+}
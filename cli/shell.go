@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrShellClosed is returned by Shell.Run once the shell has exited or was
+// closed.
+var ErrShellClosed = errors.New("shell is closed")
+
+// ShellOptions controls NewShell.
+type ShellOptions struct {
+	// Options apply to the shell process (Dir, Env, CleanEnv, Credential,
+	// ProcessGroup, Limits, ...). Timeout bounds each Run, not the session.
+	// Stdout / Stderr, if set, additionally receive all output live.
+	// CaptureOutput, Stdin, Input, PTY and Retries are ignored.
+	Options
+
+	// Shell is the interpreter: bash, sh, zsh, pwsh or powershell (name or
+	// path). Default: bash (or /bin/sh) on Unix, pwsh/powershell on Windows.
+	Shell string
+}
+
+// Shell is a persistent shell process. Commands sent with Run execute one
+// after another in the same shell, so environment variables, the working
+// directory, shell functions and aliases carry over between them.
+//
+// Commands must not read standard input (it carries the next commands),
+// and a command that exits the shell closes the session. Shell is safe for
+// concurrent use; Runs are serialized.
+type Shell struct {
+	mu         sync.Mutex
+	e          *execution
+	stdin      *os.File
+	stdout     *markerBuffer
+	stderr     *markerBuffer
+	powershell bool
+	token      string
+	seq        int
+	timeout    time.Duration
+	done       chan struct{}
+}
+
+// NewShell starts a persistent shell. Close it when done; it is also
+// killed when ctx is cancelled.
+func NewShell(ctx context.Context, opts ShellOptions) (*Shell, error) {
+	shell, args := opts.Shell, []string(nil)
+	if shell == "" {
+		if runtime.GOOS == "windows" {
+			shell = powershell()
+		} else if p, err := exec.LookPath("bash"); err == nil {
+			shell = p
+		} else {
+			shell = "/bin/sh"
+		}
+	}
+	switch shellName(shell) {
+	case "pwsh", "powershell":
+		args = []string{"-NoLogo", "-NoProfile", "-NonInteractive", "-Command", "-"}
+	case "cmd":
+		return nil, fmt.Errorf("shell %q: cmd is not supported, use powershell", shell)
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("shell token: %w", err)
+	}
+
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("create stdin pipe: %w", err)
+	}
+
+	run := withDefaults(opts.Options)
+	s := &Shell{
+		stdin:      stdinW,
+		stdout:     newMarkerBuffer(),
+		stderr:     newMarkerBuffer(),
+		powershell: isWindowsShell(shell),
+		token:      "__GOCOMMONS_" + hex.EncodeToString(token),
+		timeout:    run.Timeout,
+		done:       make(chan struct{}),
+	}
+
+	run.Stdin, run.Input = stdinR, ""
+	run.CaptureOutput, run.PTY, run.Timeout = false, false, 0
+	if run.Stdout == nil {
+		run.Stdout = io.Discard
+	}
+	if run.Stderr == nil {
+		run.Stderr = io.Discard
+	}
+	if run.LogCommand {
+		logCommand(shell, args, run)
+	}
+
+	e, err := newExecution(ctx, shell, args, run, s.stdout, s.stderr)
+	if err != nil {
+		_ = stdinR.Close()
+		_ = stdinW.Close()
+		return nil, err
+	}
+	err = e.begin()
+	// The child holds its own copy of the read end.
+	_ = stdinR.Close()
+	if err != nil {
+		_ = stdinW.Close()
+		_, err = e.finish(err)
+		return nil, err
+	}
+	s.e = e
+
+	go func() {
+		_, _ = e.wait()
+		s.stdout.Close()
+		s.stderr.Close()
+		close(s.done)
+	}()
+	return s, nil
+}
+
+// Run executes command (may span several lines) in the shell and returns
+// its output and exit status. A non-zero exit returns an *ExitError; the
+// shell stays usable. If ctx is cancelled or Options.Timeout expires, the
+// shell is killed (the command can't be interrupted on its own) and later
+// Runs return ErrShellClosed.
+func (s *Shell) Run(ctx context.Context, command string) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return &Result{ExitCode: -1}, ErrShellClosed
+	default:
+	}
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	s.seq++
+	marker := s.token + "_" + strconv.Itoa(s.seq)
+	start := time.Now()
+	if _, err := io.WriteString(s.stdin, s.script(command, marker)); err != nil {
+		return &Result{ExitCode: -1}, fmt.Errorf("%w: %w", ErrShellClosed, err)
+	}
+
+	// Stop waiting (and the shell) if ctx ends first.
+	stop := context.AfterFunc(ctx, func() {
+		_ = s.e.cmd.Process.Kill()
+	})
+	defer stop()
+
+	out, code, outErr := s.stdout.waitExit("\n" + marker + " ")
+	errOut, errErr := s.stderr.waitMarker("\n" + marker + "\n")
+
+	res := &Result{
+		Stdout:   string(out),
+		Stderr:   string(errOut),
+		ExitCode: code,
+		Duration: time.Since(start),
+		Pid:      s.e.cmd.Process.Pid,
+		Attempts: 1,
+	}
+	if err := errors.Join(outErr, errErr); err != nil {
+		res.ExitCode = -1
+		if ctx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && s.timeout > 0 {
+				return res, fmt.Errorf("%w after %s", ErrTimeout, s.timeout)
+			}
+			return res, ctx.Err()
+		}
+		return res, ErrShellClosed
+	}
+	if code != 0 {
+		return res, &ExitError{Code: code, Stderr: res.Stderr, Err: fmt.Errorf("exit status %d", code)}
+	}
+	return res, nil
+}
+
+// script wraps command so the shell reports its exit status, followed by
+// marker, on both streams. The command is evaluated in the shell itself so
+// it can change the shell's state, and syntax errors can't derail the
+// session.
+func (s *Shell) script(command, marker string) string {
+	if s.powershell {
+		b64 := base64.StdEncoding.EncodeToString([]byte(command))
+		return "$global:LASTEXITCODE = 0; " +
+			"Invoke-Expression ([Text.Encoding]::UTF8.GetString([Convert]::FromBase64String('" + b64 + "'))); " +
+			"$__gcrc = if ($?) { 0 } else { 1 }; if ($LASTEXITCODE) { $__gcrc = $LASTEXITCODE }; " +
+			"[Console]::Out.Write(\"`n" + marker + " $__gcrc`n\"); [Console]::Error.Write(\"`n" + marker + "`n\")\n"
+	}
+	eof := s.token + "_EOF"
+	return "eval \"$(cat <<'" + eof + "'\n" + command + "\n" + eof + "\n)\"\n" +
+		"__gcrc=$?; printf '\\n%s %d\\n' '" + marker + "' \"$__gcrc\"; printf '\\n%s\\n' '" + marker + "' >&2\n"
+}
+
+// Close ends the shell: it closes its input and waits briefly for it to
+// exit before killing it.
+func (s *Shell) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = s.stdin.Close()
+	select {
+	case <-s.done:
+	case <-time.After(timeoutWaitDelay):
+		_ = s.e.cmd.Process.Kill()
+		<-s.done
+	}
+	return nil
+}
+
+// Done is closed when the shell process has exited.
+func (s *Shell) Done() <-chan struct{} {
+	return s.done
+}
+
+// markerBuffer accumulates a stream and lets Shell.Run consume it up to a
+// marker line.
+type markerBuffer struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	data   []byte
+	closed bool
+}
+
+func newMarkerBuffer() *markerBuffer {
+	b := &markerBuffer{}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *markerBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	b.data = append(b.data, p...)
+	b.mu.Unlock()
+	b.cond.Broadcast()
+	return len(p), nil
+}
+
+// Close marks the end of the stream; waiting calls fail.
+func (b *markerBuffer) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// waitMarker blocks until marker appears, consumes the output up to and
+// including it and returns the output before it.
+func (b *markerBuffer) waitMarker(marker string) ([]byte, error) {
+	out, _, err := b.wait(marker, false)
+	return out, err
+}
+
+// waitExit is waitMarker for the stdout marker, which is followed by the
+// exit code and a newline.
+func (b *markerBuffer) waitExit(marker string) ([]byte, int, error) {
+	return b.wait(marker, true)
+}
+
+func (b *markerBuffer) wait(marker string, withCode bool) ([]byte, int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if out, code, ok := b.take([]byte(marker), withCode); ok {
+			return out, code, nil
+		}
+		if b.closed {
+			return bytes.Clone(b.data), -1, ErrShellClosed
+		}
+		b.cond.Wait()
+	}
+}
+
+// take consumes the output up to and including a complete marker line.
+// Callers hold mu.
+func (b *markerBuffer) take(marker []byte, withCode bool) ([]byte, int, bool) {
+	i := bytes.Index(b.data, marker)
+	if i < 0 {
+		return nil, 0, false
+	}
+	rest := b.data[i+len(marker):]
+
+	code := 0
+	if withCode {
+		nl := bytes.IndexByte(rest, '\n')
+		if nl < 0 {
+			return nil, 0, false
+		}
+		code, _ = strconv.Atoi(string(bytes.TrimSpace(rest[:nl])))
+		rest = rest[nl+1:]
+	}
+
+	out := bytes.Clone(b.data[:i])
+	b.data = append(b.data[:0], rest...)
+	return out, code, true
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ErrEmptyCommand is returned when a command template renders no command.
+var ErrEmptyCommand = errors.New("empty command")
+
+// RenderCommand splits a command-line template into arguments and renders
+// each with data (text/template syntax, missing keys are errors), e.g.
+//
+//	RenderCommand("scp {{.Src}} {{.User}}@{{.Host}}:{{.Dst}}", cfg)
+//
+// The template is split before rendering, so values never create extra
+// arguments or get interpreted by a shell: a Src of "my file.txt; rm -rf ~"
+// stays one literal argument. Single or double quotes group literal text
+// ("{{.First}} {{.Last}}" is one argument); unquoted arguments that render
+// empty are dropped, so {{if .Verbose}}-v{{end}} works.
+func RenderCommand(tmpl string, data any) (string, []string, error) {
+	tokens, err := splitTemplate(tmpl)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var argv []string
+	for _, tok := range tokens {
+		t, err := template.New("command").Option("missingkey=error").Parse(tok.text)
+		if err != nil {
+			return "", nil, fmt.Errorf("parse command template: %w", err)
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return "", nil, fmt.Errorf("render command template: %w", err)
+		}
+		if b.Len() == 0 && !tok.quoted {
+			continue
+		}
+		argv = append(argv, b.String())
+	}
+
+	if len(argv) == 0 || argv[0] == "" {
+		return "", nil, ErrEmptyCommand
+	}
+	return argv[0], argv[1:], nil
+}
+
+// RunTemplate renders tmpl with RenderCommand and runs the result (see
+// Run).
+func RunTemplate(ctx context.Context, tmpl string, data any, opts Options) (string, error) {
+	command, args, err := RenderCommand(tmpl, data)
+	if err != nil {
+		return "", err
+	}
+	return Run(ctx, command, args, opts)
+}
+
+type templateToken struct {
+	text   string // template source of one argument, quotes removed
+	quoted bool
+}
+
+// splitTemplate splits a command-line template on unquoted whitespace.
+// Template actions ({{ ... }}) are copied verbatim, even when they contain
+// spaces or quotes.
+func splitTemplate(s string) ([]templateToken, error) {
+	var (
+		tokens  []templateToken
+		cur     strings.Builder
+		inToken bool
+		quoted  bool
+		quote   rune
+	)
+	flush := func() {
+		if inToken {
+			tokens = append(tokens, templateToken{text: cur.String(), quoted: quoted})
+		}
+		cur.Reset()
+		inToken, quoted = false, false
+	}
+
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "{{") {
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return nil, errors.New("parse command template: unclosed action")
+			}
+			cur.WriteString(s[i : i+end+2])
+			inToken = true
+			i += end + 2
+			continue
+		}
+
+		r := rune(s[i])
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '\'' || r == '"'):
+			quote, quoted, inToken = r, true, true
+		case quote == 0 && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			flush()
+		default:
+			cur.WriteByte(s[i])
+			inToken = true
+		}
+		i++
+	}
+	if quote != 0 {
+		return nil, errors.New("parse command template: unclosed quote")
+	}
+	flush()
+	return tokens, nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"sync"
+	"time"
+)
+
+// gracefulStop implements Options.GracePeriod: cancellation first asks the
+// command to terminate (SIGTERM, CTRL_BREAK on Windows) and only kills it
+// when it is still running after the grace period.
+type gracefulStop struct {
+	e     *execution
+	grace time.Duration
+	kill  func() error // the hard kill (group-aware when ProcessGroup)
+
+	mu    sync.Mutex
+	timer *time.Timer
+}
+
+// install wires the graceful stop into e.cmd. Must run after the process
+// group (if any) has set its own Cancel.
+func (g *gracefulStop) install() {
+	cmd := g.e.cmd
+	g.kill = cmd.Cancel
+	if g.kill == nil {
+		g.kill = func() error { return cmd.Process.Kill() }
+	}
+	cmd.Cancel = g.cancel
+	// exec's own last resort, after our kill had its chance.
+	cmd.WaitDelay = g.grace + timeoutWaitDelay
+}
+
+func (g *gracefulStop) cancel() error {
+	if err := terminate(g.e.cmd, g.e.pg); err != nil {
+		g.e.log.Debug("Graceful termination unavailable, killing", "err", err)
+		return g.kill()
+	}
+	g.e.log.Debug("Sent termination request", "grace", g.grace)
+
+	g.mu.Lock()
+	g.timer = time.AfterFunc(g.grace, func() {
+		g.e.log.Warn("Grace period expired, killing command", "grace", g.grace)
+		_ = g.kill()
+	})
+	g.mu.Unlock()
+	return nil
+}
+
+// release runs once the command has exited. With a process group, members
+// that outlive the leader are killed now: the tree was asked to stop.
+func (g *gracefulStop) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.timer != nil && g.timer.Stop() && g.e.pg != nil {
+		_ = g.kill()
+	}
+}
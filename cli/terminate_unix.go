@@ -0,0 +1,19 @@
+//go:build unix
+
+package cli
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// terminate asks the command (its whole group with ProcessGroup) to exit.
+func terminate(cmd *exec.Cmd, pg *processGroup) error {
+	if pg != nil {
+		return pg.signal(syscall.SIGTERM)
+	}
+	return cmd.Process.Signal(syscall.SIGTERM)
+}
+
+// prepareTerminate is a no-op on unix: any process can receive SIGTERM.
+func prepareTerminate(*exec.Cmd) {}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+var procGenerateConsoleCtrlEvent = modkernel32.NewProc("GenerateConsoleCtrlEvent")
+
+const ctrlBreakEvent = 1
+
+// terminate sends CTRL_BREAK to the command's console process group, the
+// closest Windows has to SIGTERM. It fails when the processes don't share
+// a console with us, in which case the caller kills instead.
+func terminate(cmd *exec.Cmd, _ *processGroup) error {
+	r, _, err := procGenerateConsoleCtrlEvent.Call(ctrlBreakEvent, uintptr(cmd.Process.Pid))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// prepareTerminate starts the command in its own console process group so
+// CTRL_BREAK reaches only it.
+func prepareTerminate(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= createNewProcessGroup
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidConstraint is returned by RegisterTool for unparsable version
+// constraints.
+var ErrInvalidConstraint = errors.New("invalid version constraint")
+
+// ToolState classifies a tool in a ToolReport.
+type ToolState int
+
+const (
+	ToolOK       ToolState = iota // found and satisfies the constraint
+	ToolMissing                   // not found on PATH
+	ToolOutdated                  // found, but the version doesn't satisfy the constraint
+	ToolUnknown                   // found, but the version couldn't be determined
+)
+
+func (s ToolState) String() string {
+	switch s {
+	case ToolOK:
+		return "ok"
+	case ToolMissing:
+		return "missing"
+	case ToolOutdated:
+		return "outdated"
+	default:
+		return "unknown"
+	}
+}
+
+// ToolStatus is the outcome of checking one registered tool.
+type ToolStatus struct {
+	Name       string
+	Constraint string // as registered, e.g. ">= 2.30"
+	State      ToolState
+	Path       string // resolved executable, if found
+	Version    string // parsed version, e.g. "2.43.0"
+	Err        error  // why the version is unknown
+}
+
+// ToolReport is the result of CheckTools, in registration order.
+type ToolReport struct {
+	Tools []ToolStatus
+}
+
+// OK reports whether every tool is present and satisfies its constraint.
+func (r ToolReport) OK() bool {
+	for _, t := range r.Tools {
+		if t.State != ToolOK {
+			return false
+		}
+	}
+	return true
+}
+
+// Problems returns the tools that are not ToolOK.
+func (r ToolReport) Problems() []ToolStatus {
+	var out []ToolStatus
+	for _, t := range r.Tools {
+		if t.State != ToolOK {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// String renders the report one tool per line, suitable for a `doctor`
+// subcommand.
+func (r ToolReport) String() string {
+	var b strings.Builder
+	for _, t := range r.Tools {
+		line := fmt.Sprintf("%-10s %-9s", t.Name, t.State)
+		if t.Version != "" {
+			line += " " + t.Version
+		}
+		if t.Constraint != "" && t.State != ToolOK {
+			line += " (want " + t.Constraint + ")"
+		}
+		if t.Err != nil {
+			line += fmt.Sprintf(": %v", t.Err)
+		}
+		b.WriteString(strings.TrimRight(line, " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+type tool struct {
+	name        string
+	constraint  string
+	check       []versionCheck
+	versionArgs []string
+}
+
+var tools struct {
+	mu   sync.Mutex
+	list []tool
+}
+
+// RegisterTool declares a required tool for CheckTools. constraint is a
+// comma-separated list of comparisons that must all hold — ">= 2.30",
+// "^20", "~1.4", ">=1.2, <2", "1.2.3" — or "" for any version.
+// versionArgs default to --version (see Version). Registering a name again
+// replaces the earlier entry.
+func RegisterTool(name, constraint string, versionArgs ...string) error {
+	check, err := parseConstraint(constraint)
+	if err != nil {
+		return fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	tools.mu.Lock()
+	defer tools.mu.Unlock()
+
+	t := tool{name: name, constraint: constraint, check: check, versionArgs: versionArgs}
+	for i := range tools.list {
+		if tools.list[i].name == name {
+			tools.list[i] = t
+			return nil
+		}
+	}
+	tools.list = append(tools.list, t)
+	return nil
+}
+
+// CheckTools checks every registered tool: whether it is on PATH, and
+// whether its version satisfies the constraint.
+func CheckTools() ToolReport {
+	tools.mu.Lock()
+	list := append([]tool(nil), tools.list...)
+	tools.mu.Unlock()
+
+	report := ToolReport{Tools: make([]ToolStatus, len(list))}
+	for i, t := range list {
+		report.Tools[i] = checkTool(t)
+	}
+	return report
+}
+
+func checkTool(t tool) ToolStatus {
+	st := ToolStatus{Name: t.name, Constraint: t.constraint}
+
+	path, err := exec.LookPath(t.name)
+	if err != nil {
+		st.State = ToolMissing
+		return st
+	}
+	st.Path = path
+
+	if len(t.check) == 0 {
+		st.State = ToolOK
+		return st
+	}
+
+	line, err := Version(t.name, t.versionArgs...)
+	if err != nil {
+		st.State, st.Err = ToolUnknown, err
+		return st
+	}
+	v, ok := parseVersion(line)
+	if !ok {
+		st.State, st.Err = ToolUnknown, fmt.Errorf("no version number in %q", line)
+		return st
+	}
+	st.Version = v.String()
+
+	st.State = ToolOK
+	for _, c := range t.check {
+		if !c(v) {
+			st.State = ToolOutdated
+			break
+		}
+	}
+	return st
+}
+
+// semver is a major.minor.patch version; pre-release and build metadata
+// are ignored.
+type semver [3]int
+
+func (v semver) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+func (v semver) compare(o semver) int {
+	for i := range v {
+		if v[i] != o[i] {
+			if v[i] < o[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+var versionRe = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// parseVersion extracts the first version number from s, so "git version
+// 2.43.0", "v20.11.1" and "Python 3.12" all work.
+func parseVersion(s string) (semver, bool) {
+	v, _, ok := parseVersionParts(s)
+	return v, ok
+}
+
+// parseVersionParts is parseVersion that also reports how many components
+// were present.
+func parseVersionParts(s string) (semver, int, bool) {
+	m := versionRe.FindStringSubmatch(s)
+	if m == nil {
+		return semver{}, 0, false
+	}
+	var v semver
+	n := 0
+	for i := range v {
+		if m[i+1] == "" {
+			break
+		}
+		v[i], _ = strconv.Atoi(m[i+1])
+		n++
+	}
+	return v, n, true
+}
+
+type versionCheck func(semver) bool
+
+// parseConstraint compiles a constraint expression into checks that must
+// all pass.
+func parseConstraint(expr string) ([]versionCheck, error) {
+	var checks []versionCheck
+	for part := range strings.SplitSeq(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		op := strings.TrimRight(part, "0123456789.vx* ")
+		want, n, ok := parseVersionParts(part[len(op):])
+		op = strings.TrimSpace(op)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, part)
+		}
+
+		c, err := comparison(op, want, n)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, part)
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// comparison builds the check for one operator. n is the number of version
+// components given, which sets the range for ^, ~ and bare versions.
+func comparison(op string, want semver, n int) (versionCheck, error) {
+	switch op {
+	case ">=":
+		return func(v semver) bool { return v.compare(want) >= 0 }, nil
+	case ">":
+		return func(v semver) bool { return v.compare(want) > 0 }, nil
+	case "<=":
+		return func(v semver) bool { return v.compare(want) <= 0 }, nil
+	case "<":
+		return func(v semver) bool { return v.compare(want) < 0 }, nil
+	case "!=":
+		return func(v semver) bool { return v.compare(want) != 0 }, nil
+	case "^":
+		// Same major (or same minor for 0.x), at least want.
+		return func(v semver) bool {
+			if v.compare(want) < 0 || v[0] != want[0] {
+				return false
+			}
+			return want[0] != 0 || n < 2 || v[1] == want[1]
+		}, nil
+	case "~":
+		// Same major.minor (same major if only the major was given).
+		return func(v semver) bool {
+			if v.compare(want) < 0 || v[0] != want[0] {
+				return false
+			}
+			return n < 2 || v[1] == want[1]
+		}, nil
+	case "", "=", "==":
+		// "20" matches any 20.x.y, "1.2" any 1.2.x.
+		return func(v semver) bool {
+			for i := range n {
+				if v[i] != want[i] {
+					return false
+				}
+			}
+			return true
+		}, nil
+	default:
+		return nil, ErrInvalidConstraint
+	}
+}
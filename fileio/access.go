@@ -0,0 +1,76 @@
+package fileio
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+// AccessMode selects which permission CheckAccess tests for.
+type AccessMode uint32
+
+const (
+	AccessRead  AccessMode = 0x4
+	AccessWrite AccessMode = 0x2
+	AccessExec  AccessMode = 0x1
+)
+
+func (m AccessMode) String() string {
+	switch m {
+	case AccessRead:
+		return "read"
+	case AccessWrite:
+		return "write"
+	case AccessExec:
+		return "execute"
+	default:
+		return fmt.Sprintf("AccessMode(%d)", uint32(m))
+	}
+}
+
+// IsReadable reports whether the current process can read path.
+// Returns (false, nil) when the path doesn't exist or access is denied.
+func IsReadable(path string) (bool, error) {
+	return hasAccess(path, AccessRead)
+}
+
+// IsWritable reports whether the current process can write to path.
+// Returns (false, nil) when the path doesn't exist, access is denied or
+// path is on a read-only file system.
+func IsWritable(path string) (bool, error) {
+	return hasAccess(path, AccessWrite)
+}
+
+// IsExecutable reports whether the current process can execute path
+// (or search it, for directories).
+// Returns (false, nil) when the path doesn't exist or access is denied.
+func IsExecutable(path string) (bool, error) {
+	return hasAccess(path, AccessExec)
+}
+
+// CheckAccess pre-flights an operation on path. It returns nil when the
+// effective user has the requested access, otherwise an error such as
+// `access "path" (write): permission denied` that is safe to show to users.
+// The underlying cause is preserved for errors.Is (fs.ErrNotExist, fs.ErrPermission).
+func CheckAccess(path string, mode AccessMode) error {
+	if err := access(path, mode); err != nil {
+		return errx.Wrap(err, fmt.Sprintf("access %q (%s)", path, mode))
+	}
+	return nil
+}
+
+func hasAccess(path string, mode AccessMode) (bool, error) {
+	err := access(path, mode)
+	if err == nil {
+		return true, nil
+	}
+	if isNotExist(err) || errors.Is(err, fs.ErrPermission) {
+		return false, nil
+	}
+	if mode == AccessWrite && isReadOnlyFS(err) {
+		return false, nil // on a read-only mount: not writable, not a failure
+	}
+	return false, errx.Wrap(err, fmt.Sprintf("access %q (%s)", path, mode))
+}
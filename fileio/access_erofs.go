@@ -0,0 +1,13 @@
+//go:build !plan9
+
+package fileio
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isReadOnlyFS reports whether err is EROFS: path is on a read-only mount.
+func isReadOnlyFS(err error) bool {
+	return errors.Is(err, syscall.EROFS)
+}
@@ -0,0 +1,17 @@
+package fileio
+
+import "syscall"
+
+// AT_FDCWD and AT_EACCESS from <fcntl.h>. syscall only has them unexported
+// (_AT_FDCWD, _AT_EACCESS) and this module doesn't depend on x/sys; the
+// values are the same on every Linux architecture.
+const (
+	atFdCwd   = -100  // AT_FDCWD: relative paths resolve against the working directory
+	atEAccess = 0x200 // AT_EACCESS: check the effective uid/gid, not the real ones
+)
+
+// access uses faccessat(2) with AT_EACCESS so checks run against the
+// effective uid/gid (matters for setuid binaries and sudo).
+func access(path string, mode AccessMode) error {
+	return syscall.Faccessat(atFdCwd, path, uint32(mode), atEAccess)
+}
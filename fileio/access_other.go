@@ -0,0 +1,50 @@
+//go:build !unix && !windows
+
+package fileio
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// access emulates access(2) on platforms without it (js, wasip1, plan9) by
+// opening the file with the requested flags, as on Windows. Execute
+// permission is read from the mode bits; directories are always searchable.
+func access(path string, mode AccessMode) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case AccessRead:
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	case AccessWrite:
+		if fi.IsDir() {
+			f, err := os.CreateTemp(path, ".access-*")
+			if err != nil {
+				return err
+			}
+			name := f.Name()
+			_ = f.Close()
+			return os.Remove(name)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	case AccessExec:
+		if fi.IsDir() || fi.Mode().Perm()&0o111 != 0 {
+			return nil
+		}
+		return fs.ErrPermission
+	default:
+		return fmt.Errorf("unsupported access mode %d", uint32(mode))
+	}
+}
@@ -0,0 +1,7 @@
+package fileio
+
+// isReadOnlyFS reports whether err means path is on a read-only mount.
+// Plan 9 has no EROFS; such writes fail like any other permission error.
+func isReadOnlyFS(err error) bool {
+	return false
+}
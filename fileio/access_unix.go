@@ -0,0 +1,60 @@
+//go:build unix && !linux
+
+package fileio
+
+import (
+	"os"
+	"slices"
+	"syscall"
+)
+
+// access checks path against the effective uid/gid. access(2) checks the
+// real ids, so it is only used when they are the same as the effective
+// ones; in setuid/setgid programs the permission bits are compared with
+// the effective ids instead (ACLs and read-only mounts are not considered
+// there).
+func access(path string, mode AccessMode) error {
+	if os.Geteuid() == os.Getuid() && os.Getegid() == os.Getgid() {
+		return syscall.Access(path, uint32(mode))
+	}
+
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return err
+	}
+	perm := uint32(st.Mode) & 0o777
+	euid := os.Geteuid()
+
+	if euid == 0 {
+		// root may read and write anything, and execute anything that has
+		// an execute bit (or search any directory).
+		if mode&AccessExec == 0 || perm&0o111 != 0 || st.Mode&syscall.S_IFMT == syscall.S_IFDIR {
+			return nil
+		}
+		return syscall.EACCES
+	}
+
+	var bits uint32
+	switch {
+	case int(st.Uid) == euid:
+		bits = perm >> 6
+	case inGroup(int(st.Gid)):
+		bits = perm >> 3
+	default:
+		bits = perm
+	}
+	if uint32(mode)&^bits&0o7 != 0 {
+		return syscall.EACCES
+	}
+	return nil
+}
+
+// inGroup reports whether gid is the effective or a supplementary group of
+// the process.
+func inGroup(gid int) bool {
+	if gid == os.Getegid() {
+		return true
+	}
+	groups, err := os.Getgroups()
+	return err == nil && slices.Contains(groups, gid)
+}
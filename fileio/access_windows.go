@@ -0,0 +1,59 @@
+package fileio
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// access emulates access(2) on Windows by opening the file with the
+// requested flags. Execute permission is derived from PATHEXT.
+func access(path string, mode AccessMode) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case AccessRead:
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	case AccessWrite:
+		if fi.IsDir() {
+			f, err := os.CreateTemp(path, ".access-*")
+			if err != nil {
+				return err
+			}
+			name := f.Name()
+			_ = f.Close()
+			return os.Remove(name)
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	case AccessExec:
+		if fi.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		pathExt := os.Getenv("PATHEXT")
+		if pathExt == "" {
+			pathExt = ".com;.exe;.bat;.cmd"
+		}
+		for _, e := range strings.Split(strings.ToLower(pathExt), ";") {
+			if e != "" && e == ext {
+				return nil
+			}
+		}
+		return fs.ErrPermission
+	default:
+		return fmt.Errorf("unsupported access mode %d", uint32(mode))
+	}
+}
@@ -0,0 +1,141 @@
+package fileio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+// appDirKind identifies one of the per-application base directories.
+type appDirKind int
+
+const (
+	appConfig appDirKind = iota
+	appCache
+	appData
+	appState
+)
+
+// ConfigDir returns (and creates) the per-user config directory for appName:
+//
+//	Linux:   $XDG_CONFIG_HOME/<app>  (default ~/.config/<app>)
+//	macOS:   ~/Library/Application Support/<app>
+//	Windows: %APPDATA%\<app>
+func ConfigDir(appName string) (string, error) {
+	return appDir(appConfig, appName)
+}
+
+// CacheDir returns (and creates) the per-user cache directory for appName:
+//
+//	Linux:   $XDG_CACHE_HOME/<app>  (default ~/.cache/<app>)
+//	macOS:   ~/Library/Caches/<app>
+//	Windows: %LOCALAPPDATA%\<app>\cache
+func CacheDir(appName string) (string, error) {
+	return appDir(appCache, appName)
+}
+
+// DataDir returns (and creates) the per-user data directory for appName:
+//
+//	Linux:   $XDG_DATA_HOME/<app>  (default ~/.local/share/<app>)
+//	macOS:   ~/Library/Application Support/<app>
+//	Windows: %LOCALAPPDATA%\<app>\data
+func DataDir(appName string) (string, error) {
+	return appDir(appData, appName)
+}
+
+// StateDir returns (and creates) the per-user state directory for appName:
+//
+//	Linux:   $XDG_STATE_HOME/<app>  (default ~/.local/state/<app>)
+//	macOS:   ~/Library/Application Support/<app>/state
+//	Windows: %LOCALAPPDATA%\<app>\state
+func StateDir(appName string) (string, error) {
+	return appDir(appState, appName)
+}
+
+// ErrEmptyAppName is returned by the app-directory helpers when appName is blank.
+var ErrEmptyAppName = errors.New("app name must not be empty")
+
+func appDir(kind appDirKind, appName string) (string, error) {
+	if appName == "" {
+		return "", ErrEmptyAppName
+	}
+
+	base, err := appBaseDir(kind)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(base, appName)
+	switch {
+	case runtime.GOOS == "darwin" && kind == appState:
+		dir = filepath.Join(dir, "state")
+	case runtime.GOOS == "windows" && kind != appConfig:
+		dir = filepath.Join(dir, windowsSubdir(kind))
+	}
+
+	if err := EnsureDir(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func appBaseDir(kind appDirKind) (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		env := "LOCALAPPDATA"
+		if kind == appConfig {
+			env = "APPDATA"
+		}
+		if v := os.Getenv(env); v != "" {
+			return v, nil
+		}
+		return "", errx.Wrap(errors.New("%"+env+"% is not defined"), "resolve app directory")
+	case "darwin":
+		home, err := Home()
+		if err != nil {
+			return "", err
+		}
+		if kind == appCache {
+			return filepath.Join(home, "Library", "Caches"), nil
+		}
+		return filepath.Join(home, "Library", "Application Support"), nil
+	default:
+		env, fallback := xdgDir(kind)
+		// XDG spec: relative paths are invalid and must be ignored.
+		if v := os.Getenv(env); v != "" && filepath.IsAbs(v) {
+			return v, nil
+		}
+		home, err := Home()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, fallback), nil
+	}
+}
+
+func xdgDir(kind appDirKind) (env, fallback string) {
+	switch kind {
+	case appCache:
+		return "XDG_CACHE_HOME", ".cache"
+	case appData:
+		return "XDG_DATA_HOME", filepath.Join(".local", "share")
+	case appState:
+		return "XDG_STATE_HOME", filepath.Join(".local", "state")
+	default:
+		return "XDG_CONFIG_HOME", ".config"
+	}
+}
+
+func windowsSubdir(kind appDirKind) string {
+	switch kind {
+	case appCache:
+		return "cache"
+	case appData:
+		return "data"
+	default:
+		return "state"
+	}
+}
@@ -0,0 +1,170 @@
+package fileio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+// DefaultAppendBufferSize is used when AppendWriterOptions.BufferSize is 0.
+const DefaultAppendBufferSize = 64 * 1024
+
+// ErrWriterClosed is returned when writing to a closed AppendWriter.
+var ErrWriterClosed = errors.New("append writer is closed")
+
+// AppendWriterOptions configures NewAppendWriter.
+type AppendWriterOptions struct {
+	// Perm is used when the file has to be created. Defaults to 0o644.
+	Perm os.FileMode
+
+	// BufferSize is how many bytes are buffered before an automatic flush.
+	// 0 uses DefaultAppendBufferSize; a negative value disables buffering.
+	BufferSize int
+
+	// Lock takes an exclusive advisory file lock around every flush so that
+	// several processes can append to the same file without interleaving
+	// (flock, fcntl on solaris and aix, LockFileEx on Windows). It is a
+	// no-op on platforms without file locking (js, wasip1, plan9).
+	Lock bool
+}
+
+// AppendWriter serializes appends to a single file across goroutines.
+//
+// Writes are buffered and flushed as one append (a Write call is never split
+// across flushes), on Flush, on Close, or when the buffer is full.
+// Unlike AppendFile it keeps the file open between writes.
+type AppendWriter struct {
+	mu     sync.Mutex
+	f      *os.File
+	path   string
+	buf    []byte
+	size   int
+	lock   bool
+	closed bool
+}
+
+// NewAppendWriter opens (or creates) path for appending.
+func NewAppendWriter(path string, opts AppendWriterOptions) (*AppendWriter, error) {
+	perm := opts.Perm
+	if perm == 0 {
+		perm = 0o644
+	}
+	size := opts.BufferSize
+	if size == 0 {
+		size = DefaultAppendBufferSize
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return nil, errx.Wrap(err, fmt.Sprintf("open+append %q", path))
+	}
+
+	w := &AppendWriter{f: f, path: path, size: size, lock: opts.Lock}
+	if size > 0 {
+		w.buf = make([]byte, 0, size)
+	}
+	return w, nil
+}
+
+// Write buffers p and flushes when the buffer would overflow.
+// It is safe for concurrent use.
+func (w *AppendWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	if len(w.buf)+len(p) > w.size && len(w.buf) > 0 {
+		if err := w.flushLocked(); err != nil {
+			return 0, err
+		}
+	}
+	// Oversized (or unbuffered) writes go straight to the file.
+	if len(p) > w.size {
+		if err := w.appendLocked(p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// WriteString is a convenience wrapper around Write.
+func (w *AppendWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush appends any buffered data to the file.
+func (w *AppendWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.flushLocked()
+}
+
+// Sync flushes buffered data and commits the file to stable storage.
+func (w *AppendWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if err := w.flushLocked(); err != nil {
+		return err
+	}
+	if err := w.f.Sync(); err != nil {
+		return errx.Wrap(err, fmt.Sprintf("sync %q", w.path))
+	}
+	return nil
+}
+
+// Close flushes buffered data and closes the file. Calling Close twice is a no-op.
+func (w *AppendWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	flushErr := w.flushLocked()
+	if err := w.f.Close(); err != nil {
+		return errx.Wrap(err, fmt.Sprintf("close %q", w.path))
+	}
+	return flushErr
+}
+
+func (w *AppendWriter) flushLocked() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.appendLocked(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+func (w *AppendWriter) appendLocked(p []byte) error {
+	if w.lock {
+		if err := lockFile(w.f); err != nil {
+			return errx.Wrap(err, fmt.Sprintf("lock %q", w.path))
+		}
+		defer func() { _ = unlockFile(w.f) }()
+	}
+
+	if _, err := w.f.Write(p); err != nil {
+		return errx.Wrap(err, fmt.Sprintf("append %q", w.path))
+	}
+	return nil
+}
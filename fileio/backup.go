@@ -0,0 +1,148 @@
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+// BackupTimeFormat is the timestamp suffix used for backup file names,
+// e.g. "config.yaml.2024-05-01T12-00-00". It avoids ':' so names are valid on Windows.
+const BackupTimeFormat = "2006-01-02T15-04-05"
+
+// BackupOptions controls where backups go and how many are kept.
+type BackupOptions struct {
+	// Dir is where backups are written. If empty, the source file's directory is used.
+	Dir string
+
+	// MaxCount keeps at most this many backups (newest first). 0 = unlimited.
+	MaxCount int
+
+	// MaxAge removes backups older than this. 0 = unlimited.
+	MaxAge time.Duration
+}
+
+// BackupFile copies path to a timestamped sibling (or into opts.Dir) and
+// prunes older backups of the same file beyond opts.MaxCount / opts.MaxAge.
+// It returns the path of the new backup. The backup keeps the source permissions.
+func BackupFile(path string, opts BackupOptions) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", errx.Wrap(err, fmt.Sprintf("stat %q", path))
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = filepath.Dir(path)
+	} else if err := EnsureDir(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	base := filepath.Base(path)
+	stamp := now.Format(BackupTimeFormat)
+
+	existing, err := listBackups(dir, base)
+	if err != nil {
+		return "", err
+	}
+	// Several backups within the same second get an increasing numeric suffix.
+	seq := -1
+	for _, b := range existing {
+		if b.stamp == stamp && b.seq > seq {
+			seq = b.seq
+		}
+	}
+	dst := filepath.Join(dir, base+"."+stamp)
+	if seq >= 0 {
+		dst = fmt.Sprintf("%s-%d", dst, seq+1)
+	}
+
+	if err := CopyFile(path, dst, fi.Mode().Perm()); err != nil {
+		return "", err
+	}
+
+	if err := pruneBackups(dir, base, opts, now); err != nil {
+		return dst, err
+	}
+	return dst, nil
+}
+
+type backupEntry struct {
+	path  string
+	stamp string
+	seq   int
+	when  time.Time
+}
+
+// listBackups returns the backups of base found in dir, newest first.
+func listBackups(dir, base string) ([]backupEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errx.Wrap(err, fmt.Sprintf("read dir %q", dir))
+	}
+
+	prefix := base + "."
+	var backups []backupEntry
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		suffix := strings.TrimPrefix(name, prefix)
+		if len(suffix) < len(BackupTimeFormat) {
+			continue
+		}
+		stamp, rest := suffix[:len(BackupTimeFormat)], suffix[len(BackupTimeFormat):]
+		when, err := time.ParseInLocation(BackupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		seq := 0
+		if rest != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(rest, "-"))
+			if err != nil || !strings.HasPrefix(rest, "-") {
+				continue
+			}
+			seq = n
+		}
+		backups = append(backups, backupEntry{path: filepath.Join(dir, name), stamp: stamp, seq: seq, when: when})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].when.Equal(backups[j].when) {
+			return backups[i].seq > backups[j].seq
+		}
+		return backups[i].when.After(backups[j].when)
+	})
+	return backups, nil
+}
+
+func pruneBackups(dir, base string, opts BackupOptions, now time.Time) error {
+	if opts.MaxCount <= 0 && opts.MaxAge <= 0 {
+		return nil
+	}
+
+	backups, err := listBackups(dir, base)
+	if err != nil {
+		return err
+	}
+
+	for i, b := range backups {
+		tooMany := opts.MaxCount > 0 && i >= opts.MaxCount
+		tooOld := opts.MaxAge > 0 && now.Sub(b.when) > opts.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(b.path); err != nil && !isNotExist(err) {
+			return errx.Wrap(err, fmt.Sprintf("remove backup %q", b.path))
+		}
+	}
+	return nil
+}
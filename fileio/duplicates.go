@@ -0,0 +1,149 @@
+package fileio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+type duplicateOptions struct {
+	workers   int  // concurrent hashers
+	skipEmpty bool // ignore zero-byte files
+}
+
+// DuplicateOption configures FindDuplicates.
+type DuplicateOption func(*duplicateOptions)
+
+// WithHashWorkers sets how many files are hashed concurrently (default: GOMAXPROCS).
+func WithHashWorkers(n int) DuplicateOption {
+	return func(o *duplicateOptions) { o.workers = n }
+}
+
+// WithSkipEmpty ignores zero-byte files, which are otherwise all reported as duplicates of each other.
+func WithSkipEmpty() DuplicateOption {
+	return func(o *duplicateOptions) { o.skipEmpty = true }
+}
+
+// FindDuplicates walks root and groups regular files with identical content.
+//
+// Files are first grouped by size; only same-size candidates are hashed
+// (streaming SHA-256). The result maps the hex digest to the sorted list of
+// paths sharing it; unique files are not included. Symlinks are not followed.
+func FindDuplicates(root string, opts ...DuplicateOption) (map[string][]string, error) {
+	cfg := duplicateOptions{workers: runtime.GOMAXPROCS(0)}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+
+	bySize := map[int64][]string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if cfg.skipEmpty && fi.Size() == 0 {
+			return nil
+		}
+		bySize[fi.Size()] = append(bySize[fi.Size()], path)
+		return nil
+	})
+	if err != nil {
+		return nil, errx.Wrap(err, fmt.Sprintf("walk %q", root))
+	}
+
+	var candidates []string
+	for _, paths := range bySize {
+		if len(paths) > 1 {
+			candidates = append(candidates, paths...)
+		}
+	}
+
+	digests, err := hashFiles(candidates, cfg.workers)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string][]string{}
+	for path, sum := range digests {
+		groups[sum] = append(groups[sum], path)
+	}
+	for sum, paths := range groups {
+		if len(paths) < 2 {
+			delete(groups, sum)
+			continue
+		}
+		sort.Strings(paths)
+	}
+	return groups, nil
+}
+
+// hashFiles computes SHA-256 digests for paths using a bounded worker pool.
+func hashFiles(paths []string, workers int) (map[string]string, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		out      = make(map[string]string, len(paths))
+		jobs     = make(chan string)
+	)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				sum, err := hashFile(path)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				} else if err == nil {
+					out[path] = sum
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, p := range paths {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errx.Wrap(err, fmt.Sprintf("open %q", path))
+	}
+	defer errx.CloseQuietly(f, "close file", "path", path)
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errx.Wrap(err, fmt.Sprintf("hash %q", path))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,167 @@
+package fileio
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+// sniffLen is how many leading bytes are inspected (same as net/http).
+const sniffLen = 512
+
+// FileType describes the detected content type of a file.
+type FileType struct {
+	// MIME is the media type without parameters, e.g. "image/png".
+	MIME string
+
+	// Charset is the charset parameter when known (e.g. "utf-8"), otherwise "".
+	Charset string
+
+	// FromExtension is true when the type came from the file extension
+	// because the content itself was not conclusive.
+	FromExtension bool
+}
+
+// textLikeTypes are non-"text/*" MIME types whose content is still text.
+var textLikeTypes = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/javascript": true,
+	"application/x-yaml":     true,
+	"application/yaml":       true,
+	"application/toml":       true,
+	"application/x-sh":       true,
+	"image/svg+xml":          true,
+}
+
+var archiveTypes = map[string]bool{
+	"application/zip":              true,
+	"application/x-gzip":           true,
+	"application/gzip":             true,
+	"application/x-tar":            true,
+	"application/x-rar-compressed": true,
+	"application/x-7z-compressed":  true,
+	"application/x-bzip2":          true,
+	"application/x-xz":             true,
+	"application/zstd":             true,
+}
+
+// IsText reports whether the content is human-readable text.
+func (t FileType) IsText() bool {
+	return strings.HasPrefix(t.MIME, "text/") || textLikeTypes[t.MIME]
+}
+
+// IsBinary is the negation of IsText.
+func (t FileType) IsBinary() bool {
+	return !t.IsText()
+}
+
+// IsImage reports whether the content is an image (image/*).
+func (t FileType) IsImage() bool {
+	return strings.HasPrefix(t.MIME, "image/")
+}
+
+// IsArchive reports whether the content is a common archive/compression format.
+func (t FileType) IsArchive() bool {
+	return archiveTypes[t.MIME]
+}
+
+func (t FileType) String() string {
+	if t.Charset != "" {
+		return t.MIME + "; charset=" + t.Charset
+	}
+	return t.MIME
+}
+
+// DetectType sniffs the first 512 bytes of path (magic bytes, as in
+// http.DetectContentType). When the content is inconclusive
+// (generic text or octet-stream), the file extension is used as a fallback.
+func DetectType(path string) (FileType, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return FileType{}, errx.Wrap(err, fmt.Sprintf("open %q", path))
+	}
+	defer errx.CloseQuietly(f, "close file", "path", path)
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return FileType{}, errx.Wrap(err, fmt.Sprintf("read %q", path))
+	}
+	return DetectTypeBytes(buf[:n], filepath.Ext(path)), nil
+}
+
+// DetectTypeBytes is DetectType for in-memory content (e.g. uploads).
+// ext may be empty; it is only used when sniffing is inconclusive.
+func DetectTypeBytes(data []byte, ext string) FileType {
+	sniffed := parseMediaType(http.DetectContentType(data))
+
+	generic := sniffed.MIME == "application/octet-stream" || sniffed.MIME == "text/plain"
+	if !generic || ext == "" {
+		return sniffed
+	}
+
+	byExt := mime.TypeByExtension(strings.ToLower(normDotExt(ext)))
+	if byExt == "" {
+		return sniffed
+	}
+	fromExt := parseMediaType(byExt)
+	// The extension may only refine the sniffed kind, never flip text <-> binary
+	// (e.g. "go.mod" is text, not audio/x-mod).
+	if fromExt.IsText() != sniffed.IsText() {
+		return sniffed
+	}
+	fromExt.FromExtension = true
+	if fromExt.Charset == "" && sniffed.MIME == "text/plain" {
+		fromExt.Charset = sniffed.Charset
+	}
+	return fromExt
+}
+
+// IsTextFile reports whether path contains text.
+func IsTextFile(path string) (bool, error) {
+	t, err := DetectType(path)
+	if err != nil {
+		return false, err
+	}
+	return t.IsText(), nil
+}
+
+// IsBinaryFile reports whether path contains binary (non-text) data.
+func IsBinaryFile(path string) (bool, error) {
+	t, err := DetectType(path)
+	if err != nil {
+		return false, err
+	}
+	return t.IsBinary(), nil
+}
+
+// IsImageFile reports whether path contains an image.
+func IsImageFile(path string) (bool, error) {
+	t, err := DetectType(path)
+	if err != nil {
+		return false, err
+	}
+	return t.IsImage(), nil
+}
+
+func parseMediaType(v string) FileType {
+	mt, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		return FileType{MIME: v}
+	}
+	return FileType{MIME: mt, Charset: strings.ToLower(params["charset"])}
+}
+
+func normDotExt(ext string) string {
+	if ext != "" && ext[0] != '.' {
+		return "." + ext
+	}
+	return ext
+}
@@ -0,0 +1,24 @@
+//go:build solaris || aix
+
+package fileio
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive advisory lock with fcntl(2) (there is no
+// flock(2) here), blocking until available. Unlike flock, fcntl locks are
+// per process: they don't exclude other writers in the same process.
+func lockFile(f *os.File) error {
+	return fcntlLock(f, syscall.F_WRLCK)
+}
+
+func unlockFile(f *os.File) error {
+	return fcntlLock(f, syscall.F_UNLCK)
+}
+
+func fcntlLock(f *os.File, typ int16) error {
+	lk := syscall.Flock_t{Type: typ, Whence: 0} // Start 0, Len 0: the whole file
+	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLKW, &lk)
+}
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package fileio
+
+import "os"
+
+// lockFile is a no-op where the platform has no file locking (js, wasip1,
+// plan9): appends are then only serialized within the process.
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) error { return nil }
@@ -0,0 +1,133 @@
+package fileio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+// ManifestEntry records the state of one file in a Manifest.
+type ManifestEntry struct {
+	Size   int64       `json:"size"`
+	Mode   os.FileMode `json:"mode"`
+	SHA256 string      `json:"sha256"`
+}
+
+// Manifest maps slash-separated paths (relative to the root) to their entries.
+// It serializes to JSON as-is, e.g. with json.Marshal or WriteManifest.
+type Manifest struct {
+	Files map[string]ManifestEntry `json:"files"`
+}
+
+// ManifestDiff is the result of VerifyManifest. All lists are sorted.
+type ManifestDiff struct {
+	Added    []string `json:"added,omitempty"`    // on disk, not in the manifest
+	Removed  []string `json:"removed,omitempty"`  // in the manifest, not on disk
+	Modified []string `json:"modified,omitempty"` // size, mode or content differs
+}
+
+// Clean reports whether the tree matches the manifest exactly.
+func (d ManifestDiff) Clean() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// BuildManifest walks root and records size, permission bits and SHA-256 of
+// every regular file. Symlinks and other special files are skipped.
+func BuildManifest(root string) (Manifest, error) {
+	m := Manifest{Files: map[string]ManifestEntry{}}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		entry, err := manifestEntry(path, d)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		m.Files[filepath.ToSlash(rel)] = entry
+		return nil
+	})
+	if err != nil {
+		return Manifest{}, errx.Wrap(err, fmt.Sprintf("manifest %q", root))
+	}
+	return m, nil
+}
+
+// VerifyManifest compares the tree under root against m and reports
+// added, removed and modified files.
+func VerifyManifest(root string, m Manifest) (ManifestDiff, error) {
+	current, err := BuildManifest(root)
+	if err != nil {
+		return ManifestDiff{}, err
+	}
+
+	var diff ManifestDiff
+	for path, want := range m.Files {
+		got, ok := current.Files[path]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, path)
+		case got != want:
+			diff.Modified = append(diff.Modified, path)
+		}
+	}
+	for path := range current.Files {
+		if _, ok := m.Files[path]; !ok {
+			diff.Added = append(diff.Added, path)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Modified)
+	return diff, nil
+}
+
+// WriteManifest writes m as indented JSON to path.
+func WriteManifest(path string, m Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errx.Wrap(err, "encode manifest")
+	}
+	return WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// ReadManifest loads a manifest written by WriteManifest.
+func ReadManifest(path string) (Manifest, error) {
+	b, err := ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return Manifest{}, errx.Wrap(err, fmt.Sprintf("decode manifest %q", path))
+	}
+	if m.Files == nil {
+		m.Files = map[string]ManifestEntry{}
+	}
+	return m, nil
+}
+
+func manifestEntry(path string, d fs.DirEntry) (ManifestEntry, error) {
+	fi, err := d.Info()
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	sum, err := hashFile(path)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	return ManifestEntry{Size: fi.Size(), Mode: fi.Mode().Perm(), SHA256: sum}, nil
+}
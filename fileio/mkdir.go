@@ -3,6 +3,8 @@ package fileio
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 
 	"github.com/toobprojects/go-commons/errx"
 )
@@ -13,3 +15,118 @@ func EnsureDir(path string, perm os.FileMode) error {
 	}
 	return nil
 }
+
+// EnsureDirOptions controls EnsureDirWith. The zero value creates
+// directories with mode 0755 and leaves ownership alone.
+type EnsureDirOptions struct {
+	// Perm is applied to the leaf directory. If 0, 0755 is used.
+	Perm os.FileMode
+
+	// ParentPerm is applied to intermediate directories that have to be
+	// created. If 0, Perm is used.
+	ParentPerm os.FileMode
+
+	// Chown enables UID / GID; without it ownership is never changed.
+	Chown bool
+
+	// UID / GID set the owner of the leaf when Chown is set and this call
+	// created it. Use -1 to leave either unchanged. Ownership is not
+	// supported on Windows, where it is silently left alone.
+	UID, GID int
+
+	// ChownParents also applies UID/GID to created intermediate directories.
+	// Pre-existing parents are never touched.
+	ChownParents bool
+}
+
+// EnsureDirOwned is EnsureDir plus ownership: it creates path (and missing
+// parents) with perm and chowns the created directories to uid:gid.
+// Pass -1 for uid or gid to keep it unchanged.
+func EnsureDirOwned(path string, perm os.FileMode, uid, gid int) error {
+	return EnsureDirWith(path, EnsureDirOptions{
+		Perm:         perm,
+		Chown:        true,
+		UID:          uid,
+		GID:          gid,
+		ChownParents: true,
+	})
+}
+
+// EnsureDirWith creates path with separate permissions for intermediate
+// parents and the leaf. Unlike MkdirAll, modes are applied with chmod after
+// creation so they are not reduced by the process umask. Only directories
+// this call creates are chmod'ed or chown'ed; existing ones, including one
+// created concurrently by someone else, keep their mode and owner.
+func EnsureDirWith(path string, opts EnsureDirOptions) error {
+	if opts.Perm == 0 {
+		opts.Perm = 0o755
+	}
+	if !opts.Chown {
+		opts.UID, opts.GID = -1, -1
+	}
+	parentPerm := opts.ParentPerm
+	if parentPerm == 0 {
+		parentPerm = opts.Perm
+	}
+
+	path = filepath.Clean(path)
+
+	// Collect missing directories, leaf first.
+	var missing []string
+	for p := path; ; p = filepath.Dir(p) {
+		exists, err := Exists(p)
+		if err != nil {
+			return err
+		}
+		if exists {
+			break
+		}
+		missing = append(missing, p)
+		if filepath.Dir(p) == p {
+			break
+		}
+	}
+
+	// Create from the top down.
+	for i := len(missing) - 1; i >= 0; i-- {
+		dir := missing[i]
+		isLeaf := i == 0
+
+		perm := parentPerm
+		if isLeaf {
+			perm = opts.Perm
+		}
+		if err := os.Mkdir(dir, perm); err != nil {
+			if os.IsExist(err) {
+				continue // not ours: leave its mode and owner alone
+			}
+			return errx.Wrap(err, fmt.Sprintf("mkdir %q", dir))
+		}
+		if err := os.Chmod(dir, perm); err != nil {
+			return errx.Wrap(err, fmt.Sprintf("chmod %q", dir))
+		}
+		if isLeaf || opts.ChownParents {
+			if err := chownDir(dir, opts.UID, opts.GID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if isDir, err := IsDir(path); err != nil {
+		return err
+	} else if !isDir {
+		return fmt.Errorf("mkdir %q: path exists and is not a directory", path)
+	}
+	return nil
+}
+
+// chownDir sets the owner of path; a no-op on Windows, which has no uid/gid.
+func chownDir(path string, uid, gid int) error {
+	if (uid < 0 && gid < 0) || runtime.GOOS == "windows" {
+		return nil
+	}
+	if err := os.Chown(path, uid, gid); err != nil {
+		return errx.Wrap(err, fmt.Sprintf("chown %q", path))
+	}
+	return nil
+}
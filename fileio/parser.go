@@ -20,7 +20,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -30,9 +32,14 @@ import (
 // =====================
 
 type parseOptions struct {
-	strict     bool   // fail on unknown fields
-	envExpand  bool   // expand ${VAR} before parsing
-	readerName string // for error context (e.g., filename)
+	strict     bool            // fail on unknown fields
+	envExpand  bool            // expand ${VAR} before parsing
+	readerName string          // for error context (e.g., filename)
+	validate   func(any) error // run after a successful decode
+
+	// WatchConfig only
+	pollInterval time.Duration // how often the file is stat'ed
+	debounce     time.Duration // how long the file must be stable before reloading
 }
 
 // Option configures parsing behavior.
@@ -46,6 +53,21 @@ func WithStrict() Option { return func(o *parseOptions) { o.strict = true } }
 // WithEnvExpand expands ${VAR} occurrences in the raw content prior to decoding.
 func WithEnvExpand() Option { return func(o *parseOptions) { o.envExpand = true } }
 
+// WithValidate runs fn on the decoded value; a non-nil error fails the parse.
+// The type parameter must match the T being parsed, otherwise the parse fails
+// with ErrValidatorType.
+func WithValidate[T any](fn func(T) error) Option {
+	return func(o *parseOptions) {
+		o.validate = func(v any) error {
+			t, ok := v.(T)
+			if !ok {
+				return fmt.Errorf("%w: validator takes %T, parsed %T", ErrValidatorType, t, v)
+			}
+			return fn(t)
+		}
+	}
+}
+
 // =====================
 /* Public API */
 // =====================
@@ -60,7 +82,7 @@ func ParseFile[T any](path string, opts ...Option) (T, error) {
 	if err != nil {
 		return zero, fmt.Errorf("read %q: %w", path, err)
 	}
-	return ParseBytes[T](data, filepath.Ext(path), append(opts, withReaderName(path))...)
+	return ParseBytes[T](data, filepath.Ext(path), append(slices.Clip(opts), withReaderName(path))...)
 }
 
 // ParseReader reads from r as JSON/YAML based on ext (".json", ".yaml", ".yml").
@@ -87,14 +109,28 @@ func ParseBytes[T any](data []byte, ext string, opts ...Option) (T, error) {
 		data = []byte(os.ExpandEnv(string(data)))
 	}
 
+	var (
+		out T
+		err error
+	)
 	switch strings.ToLower(normExt(ext)) {
 	case ".json":
-		return parseJSON[T](data, cfg)
+		out, err = parseJSON[T](data, cfg)
 	case ".yaml", ".yml":
-		return parseYAML[T](data, cfg)
+		out, err = parseYAML[T](data, cfg)
 	default:
 		return zero, fmt.Errorf("%w: %s (expected .json, .yaml, .yml)", ErrUnsupportedExt, ext)
 	}
+	if err != nil {
+		return out, err
+	}
+
+	if cfg.validate != nil {
+		if err := cfg.validate(out); err != nil {
+			return zero, wrapWhere("validate", cfg.readerName, err)
+		}
+	}
+	return out, nil
 }
 
 // ParseString parses JSON/YAML from a string using the given extension.
@@ -114,6 +150,10 @@ func ParseStringAuto[T any](content string, opts ...Option) (T, error) {
 
 var ErrUnsupportedExt = errors.New("unsupported file extension")
 
+// ErrValidatorType is returned when a WithValidate func doesn't take the
+// type being parsed.
+var ErrValidatorType = errors.New("validator type does not match parsed type")
+
 // =====================
 // Internals
 // =====================
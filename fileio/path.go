@@ -34,6 +34,21 @@ func ExpandHome(path string) (string, error) {
 	return filepath.Join(home, path[2:]), nil
 }
 
+// ExpandPath expands $VAR / ${VAR} and a leading ~, then returns the
+// absolute, cleaned path. Unset variables expand to "".
+func ExpandPath(path string) (string, error) {
+	expanded, err := ExpandHome(os.ExpandEnv(path))
+	if err != nil {
+		return "", err
+	}
+
+	abs, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", errx.Wrap(err, fmt.Sprintf("abs %q", expanded))
+	}
+	return abs, nil
+}
+
 func Stat(path string) (os.FileInfo, error) {
 	fi, err := os.Lstat(path)
 	if err != nil {
@@ -0,0 +1,122 @@
+package fileio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/toobprojects/go-commons/errx"
+)
+
+// RelTo returns target relative to base after resolving both to absolute,
+// symlink-free paths. Paths that don't exist yet are resolved through their
+// deepest existing ancestor. On case-insensitive platforms (macOS, Windows)
+// path components are compared ignoring case.
+func RelTo(base, target string) (string, error) {
+	b, err := resolvePath(base)
+	if err != nil {
+		return "", err
+	}
+	t, err := resolvePath(target)
+	if err != nil {
+		return "", err
+	}
+
+	if !caseInsensitiveFS() {
+		rel, err := filepath.Rel(b, t)
+		if err != nil {
+			return "", errx.Wrap(err, fmt.Sprintf("rel %q -> %q", base, target))
+		}
+		return rel, nil
+	}
+	return relFold(b, t)
+}
+
+// IsSubPath reports whether candidate is base itself or lives inside it,
+// after resolving symlinks (so "workspace/link-to-etc/passwd" is not inside
+// "workspace"). Use it for "is this inside the workspace?" guards.
+func IsSubPath(base, candidate string) (bool, error) {
+	rel, err := RelTo(base, candidate)
+	if err != nil {
+		return false, err
+	}
+	if rel == "." {
+		return true, nil
+	}
+	up := ".." + string(filepath.Separator)
+	return rel != ".." && !strings.HasPrefix(rel, up) && !filepath.IsAbs(rel), nil
+}
+
+// resolvePath makes p absolute and evaluates symlinks in its longest existing prefix.
+func resolvePath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", errx.Wrap(err, fmt.Sprintf("abs %q", p))
+	}
+
+	existing, rest := abs, ""
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		} else if !isNotExist(err) {
+			return "", errx.Wrap(err, fmt.Sprintf("lstat %q", existing))
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return abs, nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", errx.Wrap(err, fmt.Sprintf("resolve symlinks %q", existing))
+	}
+	return filepath.Join(resolved, rest), nil
+}
+
+// caseInsensitiveFS reports whether the default filesystem of the platform
+// is case-insensitive. This is a platform heuristic: case-sensitive APFS
+// volumes and case-insensitive mounts on Linux are not detected.
+func caseInsensitiveFS() bool {
+	return runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
+
+// relFold is filepath.Rel for absolute, clean paths with case-insensitive component matching.
+func relFold(base, target string) (string, error) {
+	if !strings.EqualFold(filepath.VolumeName(base), filepath.VolumeName(target)) {
+		return "", fmt.Errorf("rel %q -> %q: paths are on different volumes", base, target)
+	}
+
+	bs := splitPath(base)
+	ts := splitPath(target)
+
+	i := 0
+	for i < len(bs) && i < len(ts) && strings.EqualFold(bs[i], ts[i]) {
+		i++
+	}
+
+	parts := make([]string, 0, len(bs)-i+len(ts)-i)
+	for range bs[i:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, ts[i:]...)
+	if len(parts) == 0 {
+		return ".", nil
+	}
+	return filepath.Join(parts...), nil
+}
+
+func splitPath(p string) []string {
+	p = strings.TrimPrefix(p, filepath.VolumeName(p))
+	var out []string
+	for _, s := range strings.Split(p, string(filepath.Separator)) {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,177 @@
+package fileio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/toobprojects/go-commons/errx"
+	"github.com/toobprojects/go-commons/logs"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultDebounce     = 250 * time.Millisecond
+)
+
+// WithPollInterval sets how often WatchConfig checks the file for changes
+// (default 1s; d <= 0 keeps the default). It only affects WatchConfig; the
+// Parse functions ignore it.
+func WithPollInterval(d time.Duration) Option {
+	return func(o *parseOptions) {
+		if d > 0 {
+			o.pollInterval = d
+		}
+	}
+}
+
+// WithDebounce sets how long the file must stay unchanged before WatchConfig
+// reloads it (default 250ms; d <= 0 keeps the default). This avoids parsing
+// half-written files. It only affects WatchConfig; the Parse functions
+// ignore it.
+func WithDebounce(d time.Duration) Option {
+	return func(o *parseOptions) {
+		if d > 0 {
+			o.debounce = d
+		}
+	}
+}
+
+// ConfigWatcher holds the last successfully loaded config of a WatchConfig call.
+type ConfigWatcher[T any] struct {
+	mu      sync.RWMutex
+	current T
+	done    chan struct{}
+}
+
+// Current returns the last config that parsed and validated successfully.
+func (w *ConfigWatcher[T]) Current() T {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Done is closed once the watcher stops (when ctx is cancelled).
+func (w *ConfigWatcher[T]) Done() <-chan struct{} {
+	return w.done
+}
+
+// WatchConfig parses path into T and keeps watching it for changes until ctx
+// is cancelled. The file is polled (no platform-specific notifier needed) and
+// reloaded once it has been stable for the debounce period.
+//
+// A reload only replaces Current when parsing and validation (WithValidate)
+// succeed; onChange then receives the new value and a nil error. When a reload
+// fails, onChange receives the previous good value and the error.
+//
+// The initial load is synchronous: if it fails, WatchConfig returns the error
+// and does not start watching. Parser options (WithStrict, WithEnvExpand, ...)
+// apply to every reload.
+func WatchConfig[T any](ctx context.Context, path string, onChange func(T, error), opts ...Option) (*ConfigWatcher[T], error) {
+	cfg := parseOptions{pollInterval: defaultPollInterval, debounce: defaultDebounce}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errx.Wrap(err, fmt.Sprintf("read %q", path))
+	}
+	initial, err := parseWatched[T](path, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ConfigWatcher[T]{current: initial, done: make(chan struct{})}
+	go w.loop(ctx, path, data, cfg, onChange, opts)
+	return w, nil
+}
+
+func (w *ConfigWatcher[T]) loop(ctx context.Context, path string, last []byte, cfg parseOptions, onChange func(T, error), opts []Option) {
+	defer close(w.done)
+
+	log := logs.WithGroup("fileio").With("path", path)
+	ticker := time.NewTicker(cfg.pollInterval)
+	defer ticker.Stop()
+
+	lastStat, _ := os.Stat(path)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			// Editors often replace files via rename; wait for it to come back.
+			lastStat = nil
+			continue
+		}
+		if lastStat != nil && fi.ModTime().Equal(lastStat.ModTime()) && fi.Size() == lastStat.Size() {
+			continue
+		}
+
+		fi, ok := waitStable(ctx, path, fi, cfg.debounce)
+		if !ok {
+			continue
+		}
+		lastStat = fi
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(data, last) {
+			continue
+		}
+		last = data
+
+		next, err := parseWatched[T](path, data, opts)
+		if err != nil {
+			log.Warn("Config reload failed, keeping previous config", "err", err)
+			if onChange != nil {
+				onChange(w.Current(), err)
+			}
+			continue
+		}
+
+		w.mu.Lock()
+		w.current = next
+		w.mu.Unlock()
+
+		log.Info("Config reloaded")
+		if onChange != nil {
+			onChange(next, nil)
+		}
+	}
+}
+
+// waitStable waits until path has not changed for the debounce period.
+func waitStable(ctx context.Context, path string, fi os.FileInfo, debounce time.Duration) (os.FileInfo, bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(debounce):
+		}
+
+		again, err := os.Stat(path)
+		if err != nil {
+			return nil, false
+		}
+		if again.ModTime().Equal(fi.ModTime()) && again.Size() == fi.Size() {
+			return again, true
+		}
+		fi = again
+	}
+}
+
+func parseWatched[T any](path string, data []byte, opts []Option) (T, error) {
+	return ParseBytes[T](data, filepath.Ext(path), append(slices.Clip(opts), withReaderName(path))...)
+}
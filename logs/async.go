@@ -0,0 +1,140 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultAsyncQueue is the queue length when Config.AsyncQueue is unset.
+const defaultAsyncQueue = 1024
+
+// asyncQueue hands records to a background goroutine that writes them to
+// the outputs.
+type asyncQueue struct {
+	base    slog.Handler // for drop reports, without any With attributes
+	jobs    chan asyncJob
+	mu      sync.RWMutex // guards closed against sends
+	closed  bool
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+type asyncJob struct {
+	h     slog.Handler
+	ctx   context.Context
+	r     slog.Record
+	flush chan struct{} // non-nil for Flush markers
+}
+
+func newAsyncQueue(base slog.Handler, size int) *asyncQueue {
+	if size <= 0 {
+		size = defaultAsyncQueue
+	}
+	q := &asyncQueue{base: base, jobs: make(chan asyncJob, size), done: make(chan struct{})}
+	go q.run()
+	return q
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for j := range q.jobs {
+		if j.flush != nil {
+			close(j.flush)
+			continue
+		}
+		_ = j.h.Handle(j.ctx, j.r)
+		if n := q.dropped.Swap(0); n > 0 {
+			r := slog.NewRecord(j.r.Time, slog.LevelWarn, "Dropped log records, async queue full", 0)
+			r.AddAttrs(slog.Int64("dropped", n))
+			_ = q.base.Handle(context.Background(), r)
+		}
+	}
+}
+
+// enqueue queues r for h. When the queue is full, debug records are
+// dropped (and counted); anything more important waits for room. After
+// close, records are written synchronously.
+func (q *asyncQueue) enqueue(ctx context.Context, h slog.Handler, r slog.Record) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return h.Handle(ctx, r)
+	}
+	j := asyncJob{h: h, ctx: context.WithoutCancel(ctx), r: r.Clone()}
+	if r.Level < slog.LevelInfo {
+		select {
+		case q.jobs <- j:
+		default:
+			q.dropped.Add(1)
+		}
+		return nil
+	}
+	q.jobs <- j
+	return nil
+}
+
+// flush blocks until everything queued so far has been written.
+func (q *asyncQueue) flush() {
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return
+	}
+	done := make(chan struct{})
+	q.jobs <- asyncJob{flush: done}
+	q.mu.RUnlock()
+	<-done
+}
+
+// close writes the remaining records and stops the goroutine.
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.jobs)
+	q.mu.Unlock()
+	<-q.done
+}
+
+// asyncHandler queues records for the wrapped output handlers.
+type asyncHandler struct {
+	h slog.Handler
+	q *asyncQueue
+}
+
+func (a *asyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return a.h.Enabled(ctx, level)
+}
+
+func (a *asyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	return a.q.enqueue(ctx, a.h, r)
+}
+
+func (a *asyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &asyncHandler{h: a.h.WithAttrs(attrs), q: a.q}
+}
+
+func (a *asyncHandler) WithGroup(name string) slog.Handler {
+	return &asyncHandler{h: a.h.WithGroup(name), q: a.q}
+}
+
+// Flush blocks until records queued in Async mode have been written and
+// pending OTLP records have been sent. It returns immediately in
+// synchronous mode without OTLP.
+func Flush() {
+	mu.RLock()
+	q, exp := async, exporter
+	mu.RUnlock()
+	if q != nil {
+		q.flush()
+	}
+	if exp != nil {
+		exp.flush()
+	}
+}
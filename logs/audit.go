@@ -0,0 +1,202 @@
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrAuditTampered is returned by VerifyAudit when the audit log has been
+// edited, truncated in the middle or reordered.
+var ErrAuditTampered = errors.New("audit log tampered")
+
+// AuditEventKey and AuditSeqKey are the keys of the event name and the
+// sequence number in audit records; AuditChainKey holds the hash chain.
+const (
+	AuditEventKey = "event"
+	AuditSeqKey   = "seq"
+	AuditChainKey = "chain"
+)
+
+var audit struct {
+	mu     sync.Mutex
+	out    io.Writer // nil = os.Stderr
+	closer io.Closer // the file opened by SetAuditFile
+	seq    uint64    // of the last record
+	chain  string    // of the last record
+}
+
+// SetAuditOutput sends Audit records to w, starting a new chain at
+// sequence number 1.
+func SetAuditOutput(w io.Writer) {
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+
+	closeAudit()
+	audit.out, audit.seq, audit.chain = w, 0, ""
+}
+
+// SetAuditFile appends Audit records to the file at path (created with
+// mode 0600). If the file already has records, the sequence and chain
+// continue from the last one, so restarts don't look like tampering.
+func SetAuditFile(path string) error {
+	seq, chain, err := lastAuditRecord(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+
+	closeAudit()
+	audit.out, audit.closer, audit.seq, audit.chain = f, f, seq, chain
+	return nil
+}
+
+// closeAudit closes the current audit file. audit.mu must be held.
+func closeAudit() {
+	if audit.closer != nil {
+		_ = closeFile(audit.closer)
+		audit.closer = nil
+	}
+}
+
+// Audit records a security- or compliance-relevant event on the audit
+// stream, separate from the application log: it is always written,
+// whatever the level, filters, sampling or hooks, to the writer set with
+// SetAuditOutput / SetAuditFile (default os.Stderr).
+//
+//	logs.Audit("user.login", "user", id, "ip", addr)
+//	// {"time":"…","seq":42,"event":"user.login","user":"u-17","ip":"10.0.0.4","chain":"9f2c…"}
+//
+// Records are JSON lines with a sequence number and a hash chain: chain
+// is the SHA-256 of the previous record's chain plus this record, so
+// deleting, editing or reordering records is detected by VerifyAudit.
+// Write errors are reported on the application log.
+func Audit(event string, attrs ...any) {
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "", 0)
+	r.AddAttrs(slog.Uint64(AuditSeqKey, audit.seq+1), slog.String(AuditEventKey, event))
+	r.Add(attrs...)
+	_ = h.Handle(context.Background(), r)
+
+	body := buf.Bytes()[:buf.Len()-2] // without "}\n", to append the chain
+	chain := auditChain(audit.chain, body)
+	line := fmt.Appendf(body, ",%q:%q}\n", AuditChainKey, chain)
+
+	out := audit.out
+	if out == nil {
+		out = os.Stderr
+	}
+	if _, err := out.Write(line); err != nil {
+		Error("Audit write failed", "event", event, "err", err)
+		return
+	}
+	audit.seq++
+	audit.chain = chain
+}
+
+func auditChain(prev string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(prev))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// splitAuditLine returns the record without its chain field, and the
+// chain.
+func splitAuditLine(line []byte) (body []byte, chain string, ok bool) {
+	marker := []byte(`,"` + AuditChainKey + `":"`)
+	i := bytes.LastIndex(line, marker)
+	if i < 0 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, "", false
+	}
+	return line[:i], string(line[i+len(marker) : len(line)-2]), true
+}
+
+// VerifyAudit checks an audit log written by Audit: sequence numbers must
+// be consecutive and every chain hash must match. It returns an error
+// wrapping ErrAuditTampered that names the first bad line. A log that
+// doesn't start at sequence number 1 (older records archived elsewhere)
+// is verified from its first record on.
+func VerifyAudit(r io.Reader) error {
+	var (
+		prev string
+		seq  uint64
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Bytes()
+		body, chain, ok := splitAuditLine(line)
+		var rec struct {
+			Seq uint64 `json:"seq"`
+		}
+		if !ok || json.Unmarshal(line, &rec) != nil {
+			return fmt.Errorf("%w: line %d: not an audit record", ErrAuditTampered, n)
+		}
+		if n == 1 && rec.Seq != 1 {
+			// The start was archived elsewhere: anchor on this record.
+			seq, prev = rec.Seq, chain
+			continue
+		}
+		if rec.Seq != seq+1 {
+			return fmt.Errorf("%w: line %d: sequence %d after %d", ErrAuditTampered, n, rec.Seq, seq)
+		}
+		if auditChain(prev, body) != chain {
+			return fmt.Errorf("%w: line %d: chain mismatch", ErrAuditTampered, n)
+		}
+		seq, prev = rec.Seq, chain
+	}
+	return sc.Err()
+}
+
+// lastAuditRecord returns the sequence number and chain of the last record
+// in the file at path, or zeros if it doesn't exist or is empty.
+func lastAuditRecord(path string) (uint64, string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return 0, "", nil
+	}
+	line := data[bytes.LastIndexByte(data, '\n')+1:]
+	_, chain, ok := splitAuditLine(line)
+	var rec struct {
+		Seq uint64 `json:"seq"`
+	}
+	if !ok || json.Unmarshal(line, &rec) != nil {
+		return 0, "", fmt.Errorf("%w: %s: last line is not an audit record", ErrAuditTampered, path)
+	}
+	return rec.Seq, chain, nil
+}
@@ -0,0 +1,63 @@
+package logs
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	helpers    sync.Map // function name → struct{}, see Helper
+	hasHelpers atomic.Bool
+	callerSkip atomic.Int32 // Config.CallerSkip
+)
+
+// Helper marks the calling function as a logging helper, like
+// testing.T.Helper: with AddSource, records it logs through the package
+// functions (Info, ErrorCtx, Fatal, ...) report the location of its
+// caller instead. Nested helpers are skipped too.
+//
+//	func logRequestError(r *http.Request, err error) {
+//		logs.Helper()
+//		logs.Error("request failed", "path", r.URL.Path, "err", err)
+//	}
+func Helper() {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:]) // skip [Callers, Helper]
+	f, _ := runtime.CallersFrames(pcs[:]).Next()
+	if _, loaded := helpers.LoadOrStore(f.Function, struct{}{}); !loaded {
+		hasHelpers.Store(true)
+	}
+}
+
+// callerPC returns the PC of the caller skip frames up (as for
+// runtime.Callers, counted from the caller of callerPC), then past
+// Config.CallerSkip frames and any functions marked with Helper.
+func callerPC(skip int) uintptr {
+	skip += 1 + int(callerSkip.Load()) // + callerPC
+	if !hasHelpers.Load() {
+		var pcs [1]uintptr
+		runtime.Callers(skip, pcs[:])
+		return pcs[0]
+	}
+
+	var pcs [32]uintptr
+	n := runtime.Callers(skip, pcs[:])
+	for _, pc := range pcs[:n] {
+		// A PC stands for several frames when calls are inlined; it
+		// belongs to a helper if its outermost function is one.
+		frames := runtime.CallersFrames([]uintptr{pc})
+		var fn string
+		for {
+			f, more := frames.Next()
+			fn = f.Function
+			if !more {
+				break
+			}
+		}
+		if _, ok := helpers.Load(fn); !ok {
+			return pc
+		}
+	}
+	return 0
+}
@@ -0,0 +1,187 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CloudOptions configures FormatGCP and FormatCloudWatch.
+type CloudOptions struct {
+	// GCPProject is the Google Cloud project ID. With it, trace_id
+	// attributes (see SetTraceExtractor) become
+	// logging.googleapis.com/trace, linking logs to Cloud Trace.
+	GCPProject string
+
+	// Namespace is the CloudWatch metric namespace for EMF metrics
+	// (default: the executable name).
+	Namespace string
+
+	// Dimensions names attributes whose values become EMF metric
+	// dimensions, e.g. []string{"service", "route"}.
+	Dimensions []string
+}
+
+// MetricsGroup is the group whose attributes FormatCloudWatch reports as
+// EMF metrics.
+const MetricsGroup = "metrics"
+
+// gcpSeverity maps slog levels to Cloud Logging severities.
+func gcpSeverity(l slog.Level) string {
+	switch {
+	case l >= slog.LevelError+4:
+		return "CRITICAL"
+	case l >= slog.LevelError:
+		return "ERROR"
+	case l >= slog.LevelWarn:
+		return "WARNING"
+	case l >= slog.LevelInfo+2:
+		return "NOTICE"
+	case l >= slog.LevelInfo:
+		return "INFO"
+	default:
+		return "DEBUG"
+	}
+}
+
+// newGCPHandler writes JSON in Cloud Logging's structured format: severity,
+// message, time, logging.googleapis.com/sourceLocation, /trace and /spanId.
+func newGCPHandler(w io.Writer, opts *slog.HandlerOptions, cloud CloudOptions) slog.Handler {
+	gcp := func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+		switch a.Key {
+		case slog.LevelKey:
+			if l, ok := a.Value.Any().(slog.Level); ok {
+				return slog.String("severity", gcpSeverity(l))
+			}
+		case slog.MessageKey:
+			a.Key = "message"
+		case slog.SourceKey:
+			a.Key = "logging.googleapis.com/sourceLocation"
+		case "trace_id":
+			if cloud.GCPProject != "" {
+				return slog.String("logging.googleapis.com/trace",
+					"projects/"+cloud.GCPProject+"/traces/"+a.Value.String())
+			}
+		case "span_id":
+			a.Key = "logging.googleapis.com/spanId"
+		}
+		return a
+	}
+
+	o := slog.HandlerOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	o.ReplaceAttr = ChainReplaceAttr(o.ReplaceAttr, gcp)
+	return slog.NewJSONHandler(w, &o)
+}
+
+// newCloudWatchHandler writes one JSON object per record with timestamp
+// (Unix milliseconds), level, message and the attributes (nested groups
+// as dotted keys). Attributes in the MetricsGroup group become CloudWatch
+// Embedded Metric Format metrics:
+//
+//	logs.Info("request done", slog.Group("metrics", "latency_ms", 12.5))
+//
+// adds "latency_ms": 12.5 and the _aws metadata that makes CloudWatch
+// extract it as a metric.
+func newCloudWatchHandler(w io.Writer, opts *slog.HandlerOptions, cloud CloudOptions) slog.Handler {
+	namespace := cloud.Namespace
+	if namespace == "" {
+		namespace = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	}
+	var mu sync.Mutex
+
+	return newFieldHandler(opts, func(r slog.Record, msg slog.Attr, fields []field) error {
+		var (
+			b       bytes.Buffer
+			metrics []string
+			dims    []string
+		)
+		b.WriteByte('{')
+		writeJSONField(&b, "timestamp", r.Time.UnixMilli(), true)
+		writeJSONField(&b, "level", r.Level.String(), false)
+		if msg.Key != "" {
+			writeJSONField(&b, "message", msg.Value.String(), false)
+		}
+		for _, f := range fields {
+			key := f.key
+			if name, ok := strings.CutPrefix(key, MetricsGroup+"."); ok {
+				key = name
+				metrics = append(metrics, name)
+			} else if slices.Contains(cloud.Dimensions, key) {
+				dims = append(dims, key)
+			}
+			writeJSONField(&b, key, jsonValue(f.value), false)
+		}
+		if len(metrics) > 0 {
+			writeJSONField(&b, "_aws", emfMetadata(r, namespace, metrics, dims), false)
+		}
+		b.WriteString("}\n")
+
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := w.Write(b.Bytes())
+		return err
+	})
+}
+
+// emfMetadata builds the _aws object of the Embedded Metric Format.
+func emfMetadata(r slog.Record, namespace string, metrics, dims []string) any {
+	type metric struct {
+		Name string `json:"Name"`
+	}
+	ms := make([]metric, len(metrics))
+	for i, m := range metrics {
+		ms[i] = metric{Name: m}
+	}
+	if dims == nil {
+		dims = []string{}
+	}
+	return map[string]any{
+		"Timestamp": r.Time.UnixMilli(),
+		"CloudWatchMetrics": []map[string]any{{
+			"Namespace":  namespace,
+			"Dimensions": [][]string{dims},
+			"Metrics":    ms,
+		}},
+	}
+}
+
+func writeJSONField(b *bytes.Buffer, key string, v any, first bool) {
+	if !first {
+		b.WriteByte(',')
+	}
+	k, _ := json.Marshal(key)
+	b.Write(k)
+	b.WriteByte(':')
+	val, err := json.Marshal(v)
+	if err != nil {
+		val, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(val)
+}
+
+// jsonValue converts a slog value for encoding/json, rendering errors and
+// durations as strings.
+func jsonValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+	}
+	return v.Any()
+}
@@ -0,0 +1,123 @@
+package logs
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorGreen  = "\033[32m"
+	colorBlue   = "\033[34m"
+	colorDim    = "\033[2m"
+)
+
+// ColorScheme is the palette of Color output: ANSI escape sequences such
+// as "\033[35m" (magenta) or "\033[1;31m" (bold red). An empty entry
+// leaves that part uncolored.
+type ColorScheme struct {
+	Debug, Info, Warn, Error string // level and message, per level
+	Key                      string // attribute keys
+	Time                     string // timestamp
+}
+
+// DefaultColorScheme returns the palette used when Config.Colors is nil:
+// blue / green / yellow / red levels with dimmed keys and timestamps.
+func DefaultColorScheme() ColorScheme {
+	return ColorScheme{
+		Debug: colorBlue,
+		Info:  colorGreen,
+		Warn:  colorYellow,
+		Error: colorRed,
+		Key:   colorDim,
+		Time:  colorDim,
+	}
+}
+
+// level returns the color for l.
+func (s ColorScheme) level(l slog.Level) string {
+	switch {
+	case l >= slog.LevelError:
+		return s.Error
+	case l >= slog.LevelWarn:
+		return s.Warn
+	case l >= slog.LevelInfo:
+		return s.Info
+	default:
+		return s.Debug
+	}
+}
+
+// paint wraps str in color; an empty color leaves it as is.
+func paint(color, str string) string {
+	if color == "" {
+		return str
+	}
+	return color + str + colorReset
+}
+
+// colorScheme returns the palette for an output; ok is false when it
+// should not be colored: Color is off, NO_COLOR is set, or the output is
+// not a terminal (unless FORCE_COLOR is set, e.g. for `| less -R`).
+func colorScheme(o Output, colors *ColorScheme) (scheme ColorScheme, ok bool) {
+	switch {
+	case !o.Color || os.Getenv("NO_COLOR") != "":
+		return ColorScheme{}, false
+	case os.Getenv("FORCE_COLOR") == "" && !isTerminal(o.Out):
+		return ColorScheme{}, false
+	case colors != nil:
+		return *colors, true
+	}
+	return DefaultColorScheme(), true
+}
+
+// newColorHandler writes slog-style text lines (time=… level=… msg=…
+// key=value) with the level and message colored by level and keys and
+// time in their scheme colors.
+func newColorHandler(w io.Writer, colors ColorScheme, opts *slog.HandlerOptions) slog.Handler {
+	var mu sync.Mutex
+	replace := func(a slog.Attr) (slog.Attr, bool) {
+		if opts != nil && opts.ReplaceAttr != nil {
+			a = opts.ReplaceAttr(nil, a)
+		}
+		return a, a.Key != ""
+	}
+
+	return newFieldHandler(opts, func(r slog.Record, msg slog.Attr, fields []field) error {
+		var b strings.Builder
+		pair := func(key, value, color string) {
+			if b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(paint(colors.Key, key+"="))
+			b.WriteString(paint(color, quoteLogfmt(value)))
+		}
+
+		lc := colors.level(r.Level)
+		if !r.Time.IsZero() {
+			if a, ok := replace(slog.Time(slog.TimeKey, r.Time)); ok {
+				pair(a.Key, logfmtValue(a.Value), colors.Time)
+			}
+		}
+		if a, ok := replace(slog.Any(slog.LevelKey, r.Level)); ok {
+			pair(a.Key, a.Value.String(), lc)
+		}
+		if msg.Key != "" {
+			pair(msg.Key, msg.Value.String(), lc)
+		}
+		for _, f := range fields {
+			pair(f.key, logfmtValue(f.value), "")
+		}
+		b.WriteByte('\n')
+
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := io.WriteString(w, b.String())
+		return err
+	})
+}
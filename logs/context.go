@@ -0,0 +1,83 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+type ctxAttrsKey struct{}
+
+// ctxFrame is one NewContext / WithScope call; frames link to the
+// context's previous frame.
+type ctxFrame struct {
+	parent *ctxFrame
+	attrs  []slog.Attr
+	ended  *atomic.Bool // WithScope: set by the end function
+	all    []slog.Attr  // this and the parents' attrs, if no frame is a scope
+}
+
+// NewContext returns a copy of ctx carrying request-scoped attributes
+// (alternating key/value pairs or slog.Attr, like With). The *Ctx helpers
+// add them to every record logged with the context, and FromContext
+// returns a logger that includes them. Nested calls accumulate.
+func NewContext(ctx context.Context, args ...any) context.Context {
+	return withFrame(ctx, args, nil)
+}
+
+func withFrame(ctx context.Context, args []any, ended *atomic.Bool) context.Context {
+	attrs := slog.Group("", args...).Value.Group()
+	if len(attrs) == 0 {
+		return ctx
+	}
+	parent, _ := ctx.Value(ctxAttrsKey{}).(*ctxFrame)
+	f := &ctxFrame{parent: parent, attrs: attrs, ended: ended}
+	if ended == nil && (parent == nil || parent.all != nil) {
+		var prev []slog.Attr
+		if parent != nil {
+			prev = parent.all
+		}
+		f.all = make([]slog.Attr, 0, len(prev)+len(attrs))
+		f.all = append(append(f.all, prev...), attrs...)
+	}
+	return context.WithValue(ctx, ctxAttrsKey{}, f)
+}
+
+// FromContext returns the global logger with the attributes stored in ctx
+// by NewContext (and its trace IDs, see SetTraceExtractor), for code that
+// passes loggers around rather than contexts.
+func FromContext(ctx context.Context) *slog.Logger {
+	l := get()
+	attrs := append(traceAttrs(ctx), contextAttrs(ctx)...)
+	if len(attrs) == 0 {
+		return l
+	}
+	return slog.New(l.Handler().WithAttrs(attrs))
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	var attrs []slog.Attr
+	if op := activeOp(ctx); op != nil {
+		attrs = append(attrs, slog.String(OpKey, op.name))
+	}
+	f, _ := ctx.Value(ctxAttrsKey{}).(*ctxFrame)
+	if f != nil && f.all != nil {
+		if attrs == nil {
+			return f.all
+		}
+		return append(attrs, f.all...)
+	}
+	var frames []*ctxFrame
+	for ; f != nil; f = f.parent {
+		if f.ended == nil || !f.ended.Load() {
+			frames = append(frames, f)
+		}
+	}
+	for i := len(frames) - 1; i >= 0; i-- {
+		attrs = append(attrs, frames[i].attrs...)
+	}
+	return attrs
+}
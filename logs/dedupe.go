@@ -0,0 +1,100 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// dedupeHandler collapses runs of identical consecutive records (same
+// level, group and message) into the first record plus one summary with
+// the repeat count.
+type dedupeHandler struct {
+	h     slog.Handler
+	d     *deduper
+	group string
+}
+
+type deduper struct {
+	window time.Duration
+	mu     sync.Mutex
+	last   sampleKey
+	h      slog.Handler // handler of the run's first record, for the summary
+	first  time.Time
+	latest time.Time
+	count  int // suppressed repeats
+	timer  *time.Timer
+}
+
+func newDedupeHandler(h slog.Handler, window time.Duration) slog.Handler {
+	if window <= 0 {
+		return h
+	}
+	return &dedupeHandler{h: h, d: &deduper{window: window}}
+}
+
+func (d *dedupeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return d.h.Enabled(ctx, level)
+}
+
+func (d *dedupeHandler) Handle(ctx context.Context, r slog.Record) error {
+	key := sampleKey{level: r.Level, group: d.group, msg: r.Message}
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	dd := d.d
+	dd.mu.Lock()
+	if key == dd.last && dd.h != nil {
+		dd.count++
+		dd.latest = now
+		if dd.timer == nil {
+			dd.timer = time.AfterFunc(dd.window, func() {
+				dd.mu.Lock()
+				defer dd.mu.Unlock()
+				dd.flush()
+				dd.h = nil // the next repeat is logged again
+			})
+		}
+		dd.mu.Unlock()
+		return nil
+	}
+	dd.flush()
+	dd.last, dd.h, dd.first, dd.latest = key, d.h, now, now
+	dd.mu.Unlock()
+
+	return d.h.Handle(ctx, r)
+}
+
+// flush logs the summary of the current run, if anything was suppressed.
+// Callers hold mu.
+func (dd *deduper) flush() {
+	if dd.timer != nil {
+		dd.timer.Stop()
+		dd.timer = nil
+	}
+	if dd.count == 0 {
+		return
+	}
+	r := slog.NewRecord(dd.latest, dd.last.level, dd.last.msg, 0)
+	r.AddAttrs(
+		slog.Int("repeated", dd.count),
+		slog.Duration("over", dd.latest.Sub(dd.first).Round(time.Millisecond)),
+	)
+	dd.count = 0
+	_ = dd.h.Handle(context.Background(), r)
+}
+
+func (d *dedupeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &dedupeHandler{h: d.h.WithAttrs(attrs), d: d.d, group: d.group}
+}
+
+func (d *dedupeHandler) WithGroup(name string) slog.Handler {
+	group := name
+	if d.group != "" {
+		group = d.group + "." + name
+	}
+	return &dedupeHandler{h: d.h.WithGroup(name), d: d.d, group: group}
+}
@@ -0,0 +1,116 @@
+package logs
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// InitFromEnv initializes the global logger from the environment (see
+// ConfigFromEnv), starting from the defaults. Invalid values are returned
+// as an error and leave the logger unchanged.
+func InitFromEnv() error {
+	cfg, err := ConfigFromEnv(defaultCfg)
+	if err != nil {
+		return err
+	}
+	Init(cfg)
+	return nil
+}
+
+// ConfigFromEnv returns base with the settings from these variables:
+//
+//	LOG_LEVEL   debug, info, warn, error, or a number (slog levels, e.g. -4)
+//	LOG_FORMAT  text, json, logfmt, pretty, gcp or cloudwatch
+//	LOG_COLOR   true / false / auto (default auto: color when Out is a terminal)
+//	LOG_FILE    append to this file instead of Out
+//	NO_COLOR    any non-empty value disables color (https://no-color.org)
+//
+// Unset variables keep base's values.
+func ConfigFromEnv(base Config) (Config, error) {
+	cfg := base
+
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		level, err := ParseLevel(v)
+		if err != nil {
+			return base, fmt.Errorf("LOG_LEVEL: %w", err)
+		}
+		cfg.Level = level
+	}
+
+	if v := os.Getenv("LOG_FORMAT"); v != "" {
+		f, err := ParseFormat(v)
+		if err != nil {
+			return base, fmt.Errorf("LOG_FORMAT: %w", err)
+		}
+		cfg.Format, cfg.JSON = f, false
+	}
+
+	color := strings.ToLower(os.Getenv("LOG_COLOR"))
+	auto := color == "" || color == "auto"
+	if !auto {
+		on, err := strconv.ParseBool(color)
+		if err != nil {
+			return base, fmt.Errorf("LOG_COLOR: invalid value %q", color)
+		}
+		cfg.Color = on
+	}
+
+	if v := os.Getenv("LOG_FILE"); v != "" {
+		f, err := os.OpenFile(v, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return base, fmt.Errorf("LOG_FILE: %w", err)
+		}
+		cfg.Out = f
+	}
+	if auto {
+		out := cfg.Out
+		if out == nil {
+			out = defaultCfg.Out
+		}
+		cfg.Color = isTerminal(out)
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		cfg.Color = false
+	}
+	return cfg, nil
+}
+
+// ParseLevel parses a level name (debug, info, warn / warning, error,
+// case-insensitive, with optional offset like "info+2") or a number.
+func ParseLevel(s string) (slog.Level, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return slog.Level(n), nil
+	}
+	if strings.EqualFold(s, "warning") {
+		return slog.LevelWarn, nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid level %q", s)
+	}
+	return l, nil
+}
+
+// ParseFormat parses a Format name: text, json, logfmt, pretty, gcp or
+// cloudwatch.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range []Format{FormatText, FormatJSON, FormatLogfmt, FormatPretty, FormatGCP, FormatCloudWatch} {
+		if strings.EqualFold(s, f.String()) {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid format %q (want text, json, logfmt, pretty, gcp or cloudwatch)", s)
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w any) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
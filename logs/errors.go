@@ -0,0 +1,102 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// errorAttr expands error-valued attributes into a group (Config.
+// ExpandErrors):
+//
+//	err.msg    the error message
+//	err.type   the error's Go type
+//	err.cause  the innermost error's message, if the error wraps others
+//	err.code   from the first error in the chain with a Code() /
+//	           ErrorCode() method
+//	err.stack  from the first error in the chain with a StackTrace() or
+//	           Stack() method
+func errorAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+	err, ok := a.Value.Any().(error)
+	if !ok || err == nil {
+		return a
+	}
+
+	attrs := []slog.Attr{
+		slog.String("msg", err.Error()),
+		slog.String("type", fmt.Sprintf("%T", err)),
+	}
+	if root := rootCause(err); root != err {
+		attrs = append(attrs, slog.String("cause", root.Error()))
+	}
+	if code, ok := errorCode(err); ok {
+		attrs = append(attrs, slog.String("code", code))
+	}
+	if stack, ok := errorStack(err); ok {
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+}
+
+// rootCause follows Unwrap (the first branch of joined errors) to the
+// innermost error.
+func rootCause(err error) error {
+	for {
+		var next error
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			next = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			if errs := u.Unwrap(); len(errs) > 0 {
+				next = errs[0]
+			}
+		}
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+// errorCode returns the code of the first error in the chain that has one.
+func errorCode(err error) (string, bool) {
+	var s interface{ Code() string }
+	if errors.As(err, &s) {
+		return s.Code(), true
+	}
+	var e interface{ ErrorCode() string }
+	if errors.As(err, &e) {
+		return e.ErrorCode(), true
+	}
+	var n interface{ Code() int }
+	if errors.As(err, &n) {
+		return strconv.Itoa(n.Code()), true
+	}
+	return "", false
+}
+
+// errorStack returns the stack of the first error in the chain that
+// carries one.
+func errorStack(err error) (any, bool) {
+	var lines interface{ StackTrace() []string }
+	if errors.As(err, &lines) {
+		return lines.StackTrace(), true
+	}
+	var raw interface{ Stack() []byte }
+	if errors.As(err, &raw) {
+		return string(raw.Stack()), true
+	}
+	return nil, false
+}
+
+// choose returns fn if on, nil otherwise.
+func choose(on bool, fn func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	if on {
+		return fn
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+var exitFunc atomic.Pointer[func(int)]
+
+// SetExitFunc replaces os.Exit for Fatal, e.g. in tests that assert on the
+// exit code. Pass nil to restore os.Exit.
+func SetExitFunc(fn func(code int)) {
+	if fn == nil {
+		exitFunc.Store(nil)
+		return
+	}
+	exitFunc.Store(&fn)
+}
+
+// Fatal logs msg at error level, flushes pending records, syncs and
+// closes log files (see Close) and exits with status 1. Deferred
+// functions do not run.
+func Fatal(msg string, args ...any) {
+	emit(context.Background(), slog.LevelError, msg, args...)
+	_ = Close()
+	if fn := exitFunc.Load(); fn != nil {
+		(*fn)(1)
+		return
+	}
+	os.Exit(1)
+}
+
+// Panic logs msg at error level, flushes pending records and panics with
+// msg.
+func Panic(msg string, args ...any) {
+	emit(context.Background(), slog.LevelError, msg, args...)
+	Flush()
+	panic(msg)
+}
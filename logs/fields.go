@@ -0,0 +1,148 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// field is a flattened attribute: nested groups are joined into a dotted
+// key ("req.id").
+type field struct {
+	key   string
+	value slog.Value
+}
+
+// fieldHandler implements the slog.Handler plumbing (levels, WithAttrs /
+// WithGroup, ReplaceAttr, AddSource) for outputs that only need a message
+// and a flat list of fields, such as logfmt and the system log.
+type fieldHandler struct {
+	opts   slog.HandlerOptions
+	groups []string // open groups, for ReplaceAttr
+	prefix string   // open groups as a key prefix, e.g. "req."
+	attrs  []field  // from WithAttrs
+	emit   func(r slog.Record, msg slog.Attr, fields []field) error
+}
+
+func newFieldHandler(opts *slog.HandlerOptions, emit func(slog.Record, slog.Attr, []field) error) *fieldHandler {
+	h := &fieldHandler{emit: emit}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *fieldHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+func (h *fieldHandler) Handle(_ context.Context, r slog.Record) error {
+	msg := slog.String(slog.MessageKey, r.Message)
+	if h.opts.ReplaceAttr != nil {
+		msg = h.opts.ReplaceAttr(nil, msg)
+	}
+
+	fields := make([]field, 0, len(h.attrs)+r.NumAttrs()+1)
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		src := slog.String(slog.SourceKey, frame.File+":"+strconv.Itoa(frame.Line))
+		if h.opts.ReplaceAttr != nil {
+			src = h.opts.ReplaceAttr(nil, src)
+		}
+		if src.Key != "" {
+			fields = append(fields, field{key: src.Key, value: src.Value})
+		}
+	}
+	fields = append(fields, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = h.flatten(fields, h.groups, h.prefix, a)
+		return true
+	})
+	return h.emit(r, msg, fields)
+}
+
+func (h *fieldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		c.attrs = h.flatten(c.attrs, h.groups, h.prefix, a)
+	}
+	return &c
+}
+
+func (h *fieldHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.groups = append(slices.Clip(h.groups), name)
+	c.prefix = h.prefix + name + "."
+	return &c
+}
+
+// flatten appends a, with nested groups expanded into dotted keys.
+func (h *fieldHandler) flatten(out []field, groups []string, prefix string, a slog.Attr) []field {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup && h.opts.ReplaceAttr != nil {
+		a = h.opts.ReplaceAttr(groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	if a.Equal(slog.Attr{}) {
+		return out
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return out
+		}
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+			prefix += a.Key + "."
+		}
+		for _, ga := range attrs {
+			out = h.flatten(out, groups, prefix, ga)
+		}
+		return out
+	}
+	return append(out, field{key: prefix + a.Key, value: a.Value})
+}
+
+// appendLogfmt appends key=value, quoting the value when it contains
+// spaces, quotes, '=' or non-printable characters (or is empty).
+func appendLogfmt(b []byte, key, value string) []byte {
+	b = append(b, key...)
+	b = append(b, '=')
+	if needsQuoting(value) {
+		return strconv.AppendQuote(b, value)
+	}
+	return append(b, value...)
+}
+
+// quoteLogfmt returns s, quoted if appendLogfmt would quote it.
+func quoteLogfmt(s string) string {
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrInvalidFilter is returned by SetFilter for expressions it can't parse.
+var ErrInvalidFilter = errors.New("invalid log filter")
+
+// activeFilter is the compiled SetFilter expression, or nil.
+var activeFilter atomic.Pointer[logFilter]
+
+type logFilter struct {
+	expr string
+	root filterNode
+}
+
+// SetFilter replaces level checks with a filter expression, changeable at
+// runtime; "" removes it. While a filter is set, a record is logged if and
+// only if it matches — levels set with SetLevel, SetGroupLevel and
+// SetLoggerLevel are ignored. For example, keep info for everything but
+// surface debug output from cli:
+//
+//	logs.SetFilter(`level>=info OR (group=cli AND level>=debug)`)
+//
+// Terms:
+//
+//	level OP name   OP is = != > >= < <=; name is debug, info, warn, error (or info+2, -4)
+//	group=name      the logger's group (WithGroup) is name or nested in it; != negates
+//	logger=name     the Named logger is name or a descendant; != negates
+//	msg=text        the message is text; msg~text: the message contains text
+//
+// Combine terms with AND, OR, NOT (or &&, ||, !) and parentheses; AND
+// binds tighter than OR. Values with spaces go in double quotes.
+func SetFilter(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		activeFilter.Store(nil)
+		updateFloor()
+		return nil
+	}
+	root, err := parseFilter(expr)
+	if err != nil {
+		return err
+	}
+	activeFilter.Store(&logFilter{expr: expr, root: root})
+	updateFloor()
+	return nil
+}
+
+// Filter returns the active filter expression, or "".
+func Filter() string {
+	if f := activeFilter.Load(); f != nil {
+		return f.expr
+	}
+	return ""
+}
+
+// filterFloor is floorVar while a filter is active: the outputs accept
+// every record and the filter decides.
+const filterFloor = slog.Level(math.MinInt32)
+
+// filterInput is what a filter sees. Before the record exists (Enabled),
+// the message is unknown.
+type filterInput struct {
+	level  slog.Level
+	group  string
+	name   string
+	msg    string
+	hasMsg bool
+}
+
+// tri is a three-valued truth value; unknown arises from msg terms in
+// Enabled.
+type tri int8
+
+const (
+	triFalse tri = iota
+	triTrue
+	triUnknown
+)
+
+type filterNode interface {
+	eval(in *filterInput) tri
+}
+
+type andNode struct{ l, r filterNode }
+type orNode struct{ l, r filterNode }
+type notNode struct{ n filterNode }
+
+func (n andNode) eval(in *filterInput) tri {
+	l := n.l.eval(in)
+	if l == triFalse {
+		return triFalse
+	}
+	r := n.r.eval(in)
+	if r == triFalse {
+		return triFalse
+	}
+	if l == triTrue && r == triTrue {
+		return triTrue
+	}
+	return triUnknown
+}
+
+func (n orNode) eval(in *filterInput) tri {
+	l := n.l.eval(in)
+	if l == triTrue {
+		return triTrue
+	}
+	r := n.r.eval(in)
+	if r == triTrue {
+		return triTrue
+	}
+	if l == triFalse && r == triFalse {
+		return triFalse
+	}
+	return triUnknown
+}
+
+func (n notNode) eval(in *filterInput) tri {
+	switch n.n.eval(in) {
+	case triTrue:
+		return triFalse
+	case triFalse:
+		return triTrue
+	}
+	return triUnknown
+}
+
+type termNode struct {
+	field string // level, group, logger, msg
+	op    string
+	value string
+	level slog.Level // parsed value for level terms
+}
+
+func (t termNode) eval(in *filterInput) tri {
+	var ok bool
+	switch t.field {
+	case "level":
+		switch t.op {
+		case "=":
+			ok = in.level == t.level
+		case "!=":
+			ok = in.level != t.level
+		case ">":
+			ok = in.level > t.level
+		case ">=":
+			ok = in.level >= t.level
+		case "<":
+			ok = in.level < t.level
+		case "<=":
+			ok = in.level <= t.level
+		}
+	case "group":
+		ok = inHierarchy(in.group, t.value) == (t.op == "=")
+	case "logger":
+		ok = inHierarchy(in.name, t.value) == (t.op == "=")
+	case "msg":
+		if !in.hasMsg {
+			return triUnknown
+		}
+		switch t.op {
+		case "=":
+			ok = in.msg == t.value
+		case "!=":
+			ok = in.msg != t.value
+		case "~":
+			ok = strings.Contains(in.msg, t.value)
+		}
+	}
+	if ok {
+		return triTrue
+	}
+	return triFalse
+}
+
+// inHierarchy reports whether the dotted path is name or nested in it.
+func inHierarchy(path, name string) bool {
+	return path == name || strings.HasPrefix(path, name+".")
+}
+
+// parseFilter compiles a SetFilter expression.
+func parseFilter(expr string) (filterNode, error) {
+	toks, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidFilter, p.toks[p.pos])
+	}
+	return n, nil
+}
+
+type filterParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *filterParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *filterParser) or() (filterNode, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); strings.EqualFold(t, "OR") || t == "||"; t = p.peek() {
+		p.next()
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = orNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *filterParser) and() (filterNode, error) {
+	l, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); strings.EqualFold(t, "AND") || t == "&&"; t = p.peek() {
+		p.next()
+		r, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l = andNode{l, r}
+	}
+	return l, nil
+}
+
+func (p *filterParser) unary() (filterNode, error) {
+	switch t := p.peek(); {
+	case strings.EqualFold(t, "NOT") || t == "!":
+		p.next()
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case t == "(":
+		p.next()
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidFilter)
+		}
+		return n, nil
+	}
+	return p.term()
+}
+
+func (p *filterParser) term() (filterNode, error) {
+	field, op, value := strings.ToLower(p.next()), p.next(), p.next()
+	if field == "" || op == "" || value == "" {
+		return nil, fmt.Errorf("%w: incomplete term", ErrInvalidFilter)
+	}
+	value = strings.Trim(value, `"`)
+
+	t := termNode{field: field, op: op, value: value}
+	switch field {
+	case "level":
+		switch op {
+		case "=", "!=", ">", ">=", "<", "<=":
+		default:
+			return nil, fmt.Errorf("%w: level %s", ErrInvalidFilter, op)
+		}
+		l, err := ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+		}
+		t.level = l
+	case "group", "logger":
+		if op != "=" && op != "!=" {
+			return nil, fmt.Errorf("%w: %s %s", ErrInvalidFilter, field, op)
+		}
+	case "msg", "message":
+		t.field = "msg"
+		if op != "=" && op != "!=" && op != "~" {
+			return nil, fmt.Errorf("%w: msg %s", ErrInvalidFilter, op)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, field)
+	}
+	return t, nil
+}
+
+// lexFilter splits an expression into words, quoted strings, operators
+// and parentheses.
+func lexFilter(s string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, string(c))
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed quote", ErrInvalidFilter)
+			}
+			toks = append(toks, s[i:i+end+2])
+			i += end + 2
+		case strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||") ||
+			strings.HasPrefix(s[i:], ">=") || strings.HasPrefix(s[i:], "<=") || strings.HasPrefix(s[i:], "!="):
+			toks = append(toks, s[i:i+2])
+			i += 2
+		case strings.IndexByte("=<>~!", c) >= 0:
+			toks = append(toks, string(c))
+			i++
+		default:
+			j := i
+			for j < len(s) && strings.IndexByte(" \t\n\r()=<>~!&|\"", s[j]) < 0 {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidFilter, s[i:i+1])
+			}
+			toks = append(toks, s[i:j])
+			i = j
+		}
+	}
+	return toks, nil
+}
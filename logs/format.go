@@ -0,0 +1,100 @@
+package logs
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Format selects how an output renders records.
+type Format int
+
+const (
+	FormatText       Format = iota // slog's text handler (key=value, Go quoting)
+	FormatJSON                     // one JSON object per line
+	FormatLogfmt                   // strict logfmt: time, lowercase level, msg, then fields
+	FormatPretty                   // multi-line, aligned key: value blocks for local development
+	FormatGCP                      // Google Cloud Logging structured JSON (severity, trace, sourceLocation)
+	FormatCloudWatch               // AWS CloudWatch JSON with Embedded Metric Format metrics
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatLogfmt:
+		return "logfmt"
+	case FormatPretty:
+		return "pretty"
+	case FormatGCP:
+		return "gcp"
+	case FormatCloudWatch:
+		return "cloudwatch"
+	default:
+		return "text"
+	}
+}
+
+// format resolves the Format / JSON pair: JSON = true is shorthand for
+// FormatJSON.
+func format(f Format, json bool) Format {
+	if f == FormatText && json {
+		return FormatJSON
+	}
+	return f
+}
+
+// newLogfmtHandler writes records as logfmt lines, the convention used by
+// Grafana Loki and Heroku:
+//
+//	time=2024-05-01T13:04:05.123Z level=info msg="request done" req.id=42
+func newLogfmtHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	var mu sync.Mutex
+	replace := func(a slog.Attr) (slog.Attr, bool) {
+		if opts != nil && opts.ReplaceAttr != nil {
+			a = opts.ReplaceAttr(nil, a)
+		}
+		return a, a.Key != ""
+	}
+
+	return newFieldHandler(opts, func(r slog.Record, msg slog.Attr, fields []field) error {
+		b := make([]byte, 0, 256)
+		if !r.Time.IsZero() {
+			if a, ok := replace(slog.Time(slog.TimeKey, r.Time)); ok {
+				b = appendLogfmt(b, a.Key, logfmtValue(a.Value))
+				b = append(b, ' ')
+			}
+		}
+		if a, ok := replace(slog.String(slog.LevelKey, strings.ToLower(r.Level.String()))); ok {
+			b = appendLogfmt(b, a.Key, logfmtValue(a.Value))
+			b = append(b, ' ')
+		}
+		if msg.Key != "" {
+			b = appendLogfmt(b, msg.Key, msg.Value.String())
+			b = append(b, ' ')
+		}
+		for _, f := range fields {
+			b = appendLogfmt(b, f.key, logfmtValue(f.value))
+			b = append(b, ' ')
+		}
+		if len(b) == 0 {
+			b = append(b, ' ')
+		}
+		b[len(b)-1] = '\n'
+
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := w.Write(b)
+		return err
+	})
+}
+
+// logfmtValue renders times as RFC 3339 with milliseconds and everything
+// else with slog's default formatting.
+func logfmtValue(v slog.Value) string {
+	if v.Kind() == slog.KindTime {
+		return v.Time().Format("2006-01-02T15:04:05.000Z07:00")
+	}
+	return v.String()
+}
@@ -0,0 +1,152 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// ringBuffer keeps the last records written to it, one Write per record.
+type ringBuffer struct {
+	mu    sync.Mutex
+	lines [][]byte
+	next  int
+	full  bool
+}
+
+func newRingBuffer(n int) *ringBuffer {
+	return &ringBuffer{lines: make([][]byte, n)}
+}
+
+func (b *ringBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines[b.next] = append(b.lines[b.next][:0], p...)
+	b.next++
+	if b.next == len(b.lines) {
+		b.next, b.full = 0, true
+	}
+	return len(p), nil
+}
+
+// snapshot returns the records oldest first.
+func (b *ringBuffer) snapshot() [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out [][]byte
+	if b.full {
+		for _, l := range b.lines[b.next:] {
+			out = append(out, bytes.Clone(l))
+		}
+	}
+	for _, l := range b.lines[:b.next] {
+		out = append(out, bytes.Clone(l))
+	}
+	return out
+}
+
+// resized returns a buffer of n records holding the newest records of b.
+func (b *ringBuffer) resized(n int) *ringBuffer {
+	nb := newRingBuffer(n)
+	for _, l := range b.snapshot() {
+		_, _ = nb.Write(l)
+	}
+	return nb
+}
+
+// historyRing returns the ring buffer for n records, reusing the current
+// one (and its records) across Init, or nil when n is 0.
+func historyRing(n int) *ringBuffer {
+	mu.RLock()
+	ring := history
+	mu.RUnlock()
+
+	switch {
+	case n <= 0:
+		return nil
+	case ring == nil:
+		return newRingBuffer(n)
+	case len(ring.lines) != n:
+		return ring.resized(n)
+	}
+	return ring
+}
+
+// historyHandler records every record at or above min in the ring buffer,
+// regardless of the active level, and passes the enabled ones on.
+type historyHandler struct {
+	ring slog.Handler // renders into the ring buffer
+	min  slog.Leveler
+	next slog.Handler
+}
+
+func newHistoryHandler(next slog.Handler, ring *ringBuffer, cfg Config, opts *slog.HandlerOptions) slog.Handler {
+	minLevel := cfg.HistoryLevel
+	if minLevel == nil {
+		minLevel = slog.LevelDebug
+	}
+	ringOpts := *opts
+	ringOpts.Level = minLevel
+	out := Output{Out: ring, JSON: cfg.JSON, Format: cfg.Format}
+	return &historyHandler{ring: newHandler(out, &ringOpts, cfg), min: minLevel, next: next}
+}
+
+func (h *historyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.min.Level() || h.next.Enabled(ctx, level)
+}
+
+func (h *historyHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= h.min.Level() {
+		_ = h.ring.Handle(ctx, r)
+	}
+	if h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *historyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &historyHandler{ring: h.ring.WithAttrs(attrs), min: h.min, next: h.next.WithAttrs(attrs)}
+}
+
+func (h *historyHandler) WithGroup(name string) slog.Handler {
+	return &historyHandler{ring: h.ring.WithGroup(name), min: h.min, next: h.next.WithGroup(name)}
+}
+
+func (h *historyHandler) withName(name string) slog.Handler {
+	next := h.next
+	if nh, ok := next.(interface{ withName(string) slog.Handler }); ok {
+		next = nh.withName(name)
+	}
+	return &historyHandler{ring: h.ring, min: h.min, next: next}
+}
+
+// Dump returns the records kept by Config.History, oldest first, in the
+// configured format (one record per line). It returns "" when history is
+// off.
+func Dump() string {
+	var b bytes.Buffer
+	_ = DumpTo(&b)
+	return b.String()
+}
+
+// DumpTo writes the records kept by Config.History to w, e.g. from a crash
+// handler or a /debug/logs endpoint.
+func DumpTo(w io.Writer) error {
+	mu.RLock()
+	ring := history
+	mu.RUnlock()
+	if ring == nil {
+		return nil
+	}
+	for _, l := range ring.snapshot() {
+		if _, err := w.Write(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// Hook receives every record that passes the level checks, after it was
+// written to the outputs. The record includes attributes added with With /
+// WithGroup. Hooks run synchronously on the logging goroutine, so slow
+// sinks should hand off to their own goroutine.
+type Hook func(ctx context.Context, r slog.Record) error
+
+type hookEntry struct {
+	id   uint64
+	hook Hook
+}
+
+var (
+	hooksMu sync.Mutex
+	hookSeq uint64
+	hooks   atomic.Pointer[[]hookEntry] // copy-on-write
+)
+
+// AddHook registers fn for all loggers of this package, including ones
+// created before the call and across Init. It returns a function that
+// removes the hook again.
+//
+//	remove := logs.AddHook(func(ctx context.Context, r slog.Record) error {
+//		if r.Level >= slog.LevelError {
+//			errorsTotal.Inc()
+//		}
+//		return nil
+//	})
+func AddHook(fn Hook) (remove func()) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	hookSeq++
+	id := hookSeq
+	list := append(loadHooks(), hookEntry{id: id, hook: fn})
+	hooks.Store(&list)
+
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+
+		var list []hookEntry
+		for _, e := range loadHooks() {
+			if e.id != id {
+				list = append(list, e)
+			}
+		}
+		hooks.Store(&list)
+	}
+}
+
+// AddHandler duplicates records to another slog.Handler (e.g. a Sentry or
+// OpenTelemetry bridge), subject to its own Enabled check. It returns a
+// function that removes it again.
+func AddHandler(h slog.Handler) (remove func()) {
+	return AddHook(func(ctx context.Context, r slog.Record) error {
+		if !h.Enabled(ctx, r.Level) {
+			return nil
+		}
+		return h.Handle(ctx, r)
+	})
+}
+
+// loadHooks returns a copy of the registered hooks.
+func loadHooks() []hookEntry {
+	if p := hooks.Load(); p != nil {
+		return append([]hookEntry(nil), (*p)...)
+	}
+	return nil
+}
+
+// hookHandler passes records on to the outputs, then to the hooks. It keeps
+// track of With / WithGroup so hooks see the complete record.
+type hookHandler struct {
+	h      slog.Handler
+	attrs  []groupedAttr // from WithAttrs
+	groups []string      // open groups
+}
+
+// groupedAttr is an attribute added while depth groups were open.
+type groupedAttr struct {
+	depth int
+	attr  slog.Attr
+}
+
+func (k *hookHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if p := hooks.Load(); p != nil && len(*p) > 0 {
+		return true
+	}
+	return k.h.Enabled(ctx, level)
+}
+
+func (k *hookHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	if k.h.Enabled(ctx, r.Level) {
+		err = k.h.Handle(ctx, r)
+	}
+
+	p := hooks.Load()
+	if p == nil || len(*p) == 0 || belowGlobal(ctx) {
+		return err // hooks follow the global level, not Output.Level
+	}
+
+	all := k.attrs[:len(k.attrs):len(k.attrs)]
+	r.Attrs(func(a slog.Attr) bool {
+		all = append(all, groupedAttr{depth: len(k.groups), attr: a})
+		return true
+	})
+	full := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	full.AddAttrs(nest(k.groups, all)...)
+
+	errs := []error{err}
+	for _, e := range *p {
+		errs = append(errs, e.hook(ctx, full.Clone()))
+	}
+	return errors.Join(errs...)
+}
+
+func (k *hookHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &hookHandler{
+		h:      k.h.WithAttrs(attrs),
+		attrs:  k.withAttrs(attrs),
+		groups: k.groups,
+	}
+}
+
+func (k *hookHandler) withAttrs(attrs []slog.Attr) []groupedAttr {
+	out := k.attrs[:len(k.attrs):len(k.attrs)]
+	for _, a := range attrs {
+		out = append(out, groupedAttr{depth: len(k.groups), attr: a})
+	}
+	return out
+}
+
+func (k *hookHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return k
+	}
+	return &hookHandler{
+		h:      k.h.WithGroup(name),
+		attrs:  k.attrs,
+		groups: append(k.groups[:len(k.groups):len(k.groups)], name),
+	}
+}
+
+// nest builds the attribute tree: each attribute ends up inside the groups
+// that were open when it was added, and attributes sharing a group share
+// one group attribute.
+func nest(groups []string, attrs []groupedAttr) []slog.Attr {
+	var inner []slog.Attr
+	for d := len(groups); d >= 0; d-- {
+		var level []slog.Attr
+		for _, a := range attrs {
+			if a.depth == d {
+				level = append(level, a.attr)
+			}
+		}
+		if d < len(groups) && len(inner) > 0 {
+			level = append(level, slog.Attr{Key: groups[d], Value: slog.GroupValue(inner...)})
+		}
+		inner = level
+	}
+	return inner
+}
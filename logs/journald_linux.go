@@ -0,0 +1,75 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// journalSocket is where systemd-journald accepts native protocol
+// datagrams.
+const journalSocket = "/run/systemd/journal/socket"
+
+type journalSink struct {
+	conn *net.UnixConn
+	tag  string
+}
+
+func openJournal(tag string) (systemSink, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &journalSink{conn: conn, tag: tag}, nil
+}
+
+// send writes one journal entry: MESSAGE, PRIORITY, SYSLOG_IDENTIFIER and
+// every field as an upper-case journal field (req.id -> REQ_ID).
+func (j *journalSink) send(level slog.Level, msg string, fields []field) error {
+	var b bytes.Buffer
+	appendJournalField(&b, "MESSAGE", msg)
+	appendJournalField(&b, "PRIORITY", strconv.Itoa(syslogPriority(level)))
+	appendJournalField(&b, "SYSLOG_IDENTIFIER", j.tag)
+	for _, f := range fields {
+		if name := journalFieldName(f.key); name != "" {
+			appendJournalField(&b, name, f.value.String())
+		}
+	}
+	_, err := j.conn.Write(b.Bytes())
+	return err
+}
+
+// appendJournalField encodes one field; values containing newlines use
+// the length-prefixed binary form.
+func appendJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalFieldName converts an attribute key to a valid journal field name:
+// upper-case letters, digits and underscores, not starting with an
+// underscore or digit. It returns "" if nothing is left.
+func journalFieldName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9' && b.Len() > 0:
+			b.WriteRune(r)
+		case b.Len() > 0:
+			b.WriteByte('_')
+		}
+	}
+	return strings.TrimRight(b.String(), "_")
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package logs
+
+func openJournal(string) (systemSink, error) {
+	return nil, ErrSystemLogUnsupported
+}
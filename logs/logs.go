@@ -2,17 +2,131 @@ package logs
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
+	"regexp"
+	"slices"
 	"sync"
+	"time"
 )
 
 type Config struct {
-	Level slog.Leveler // slog.LevelDebug, slog.LevelInfo, etc.
-	JSON  bool         // true = JSON handler, false = human-readable text
+	Level slog.Leveler // slog.LevelDebug, slog.LevelInfo, etc. (read once; see SetLevel)
+	JSON  bool         // true = JSON handler, false = human-readable text (shorthand for Format)
 	Out   io.Writer    // usually os.Stdout or os.Stderr
-	Color bool         // enable ANSI colors in text mode (ignored for JSON)
+	Color bool         // enable ANSI colors in text mode (ignored for JSON; off when NO_COLOR is set or Out isn't a terminal)
+
+	// Format selects text, JSON or logfmt output (JSON = true is the same
+	// as FormatJSON).
+	Format Format
+
+	// Colors is the palette for Color output (default DefaultColorScheme).
+	Colors *ColorScheme
+
+	// Cloud configures FormatGCP and FormatCloudWatch (project ID for trace
+	// links, EMF namespace and dimensions).
+	Cloud CloudOptions
+
+	// TimeFormat is the timestamp layout (time.RFC3339, time.Kitchen, any
+	// custom layout) or TimeUnix / TimeUnixMilli / TimeUnixNano for numbers.
+	// Default: slog's (RFC 3339 with milliseconds in text).
+	TimeFormat string
+
+	// TimeZone converts timestamps, e.g. time.UTC (default: local time).
+	TimeZone *time.Location
+
+	// NoTime omits the timestamp, e.g. under systemd, which adds its own.
+	NoTime bool
+
+	// AddSource adds the caller's file:line to every record (the "source"
+	// attribute), pointing at the code that called Info, Error, etc.
+	AddSource bool
+
+	// RedactKeys masks the values of attributes with these names
+	// (case-insensitive), e.g. DefaultRedactKeys. A matching group masks
+	// everything in it.
+	RedactKeys []string
+
+	// RedactPatterns masks matches in messages and string values, e.g. a
+	// bearer token or credit card number regexp.
+	RedactPatterns []*regexp.Regexp
+
+	// ExpandErrors renders error attributes as a group with the message,
+	// type, root cause and, when the chain provides them, code and stack
+	// (err.msg, err.cause, ...) instead of one string.
+	ExpandErrors bool
+
+	// ReplaceAttr rewrites or drops attributes before output, like
+	// slog.HandlerOptions.ReplaceAttr (built-in keys included). It runs
+	// after the package's own transformations (time format, redaction,
+	// error expansion); combine several with ChainReplaceAttr.
+	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	// Sampling caps identical messages per time window (off by default).
+	Sampling Sampling
+
+	// SystemLog additionally sends every record to the local syslog daemon
+	// or systemd-journald, with slog levels mapped to syslog priorities.
+	// If it can't be reached, a warning is logged to the other outputs.
+	SystemLog SystemLog
+
+	// SystemLogTag identifies the program in the system log (default: the
+	// executable name).
+	SystemLogTag string
+
+	// Dedupe collapses runs of identical consecutive messages (same level,
+	// group and message) into the first record plus a summary with
+	// repeated=N and over=<duration>. The summary is written when a
+	// different message arrives or at most Dedupe after the run started.
+	// 0 disables it.
+	Dedupe time.Duration
+
+	// StackTrace attaches the call stack to error (and above) records.
+	StackTrace StackTrace
+
+	// History keeps the last History records in memory for Dump / DumpTo,
+	// including ones below the active level down to HistoryLevel (default:
+	// debug).
+	History      int
+	HistoryLevel slog.Leveler
+
+	// Async writes records from a background goroutine through a bounded
+	// queue (AsyncQueue records, default 1024), so logging calls don't wait
+	// for slow outputs. When the queue is full, debug records are dropped
+	// and other levels wait. Call Flush / Close before exiting, or queued
+	// records are lost.
+	Async      bool
+	AsyncQueue int
+
+	// CallerSkip is the number of extra stack frames to skip when
+	// AddSource determines the caller, for packages that wrap logs.* in
+	// their own functions (see also Helper).
+	CallerSkip int
+
+	// OTLP additionally exports records to an OpenTelemetry collector
+	// over OTLP/HTTP, batched in the background (nil = off). Flush and
+	// Close send what is pending.
+	OTLP *OTLP
+
+	// Outputs are additional destinations that receive every record
+	// alongside Out, each with its own format (e.g. text+color to the
+	// console and JSON to a file).
+	Outputs []Output
+}
+
+// Output is an extra log destination for Config.Outputs.
+type Output struct {
+	Out    io.Writer
+	JSON   bool   // true = JSON handler, false = human-readable text
+	Color  bool   // enable ANSI colors in text mode (ignored for JSON)
+	Format Format // text, JSON or logfmt (JSON = true is the same as FormatJSON)
+
+	// Level is this output's minimum level, independent of Config.Level,
+	// SetLevel and filters: e.g. debug to a file while the console stays
+	// at info. nil follows the global level.
+	Level slog.Leveler
 }
 
 var (
@@ -26,77 +140,165 @@ var (
 	}
 	// currentCfg holds the last active config (initialized by Init).
 	currentCfg = Config{}
+	// async is the queue of the active logger in Async mode.
+	async *asyncQueue
+	// history is the ring buffer of Config.History.
+	history *ringBuffer
+	// exporter sends records to Config.OTLP.
+	exporter *otlpExporter
+	// owned are the files opened by SetLogFile, SetRotatingLogFile and
+	// AddLogFile, closed by Close.
+	owned []io.WriteCloser
 )
 
-const (
-	colorReset  = "\033[0m"
-	colorRed    = "\033[31m"
-	colorYellow = "\033[33m"
-	colorGreen  = "\033[32m"
-	colorBlue   = "\033[34m"
-)
+// SetLogFile redirects output to an append-only file at path, keeping the
+// current level and format. The file grows without bound; see
+// SetRotatingLogFile for long-running processes.
+func SetLogFile(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	own(f)
+	setOutput(f)
+	return nil
+}
 
-// colorHandler wraps another slog.Handler and injects ANSI color
-// codes into the log message based on the log level.
-//
-// This is only used when Config.Color is true and JSON is false.
-type colorHandler struct {
-	h slog.Handler
+// SetRotatingLogFile is SetLogFile with rotation: the file is rolled over
+// and old files are compressed / pruned according to r.
+func SetRotatingLogFile(path string, r Rotation) error {
+	w, err := OpenRotatingFile(path, r)
+	if err != nil {
+		return err
+	}
+	own(w)
+	setOutput(w)
+	return nil
 }
 
-func (c *colorHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return c.h.Enabled(ctx, level)
+// AddLogFile opens an append-only file at path as an additional output
+// in the current format (without colors), so logs go to both the console
+// and the file.
+func AddLogFile(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	own(f)
+	cfg := activeConfig()
+	AddOutput(Output{Out: f, JSON: cfg.JSON, Format: cfg.Format})
+	return nil
 }
 
-func (c *colorHandler) Handle(ctx context.Context, r slog.Record) error {
-	// Work on a copy of the record so we don't mutate the original.
-	rec := r.Clone()
+// AddOutput adds a destination to the current configuration without
+// replacing the existing ones.
+func AddOutput(o Output) {
+	cfg := activeConfig()
+	cfg.Outputs = append(cfg.Outputs[:len(cfg.Outputs):len(cfg.Outputs)], o)
+	Init(cfg)
+}
 
-	var color string
-	switch {
-	case rec.Level >= slog.LevelError:
-		color = colorRed
-	case rec.Level >= slog.LevelWarn:
-		color = colorYellow
-	case rec.Level >= slog.LevelInfo:
-		color = colorGreen
-	default:
-		color = colorBlue
+// activeConfig returns the last config passed to Init, or defaultCfg.
+func activeConfig() Config {
+	mu.RLock()
+	cfg := currentCfg
+	mu.RUnlock()
+	// If currentCfg is zero (not initialized), use defaultCfg.
+	if cfg.Out == nil && cfg.Level == nil {
+		cfg = defaultCfg
 	}
+	return cfg
+}
 
-	if color != "" {
-		rec.Message = color + rec.Message + colorReset
-	}
+// setOutput reinitializes the logger with the last active config but a new
+// output. A previous output opened by this package is closed.
+func setOutput(w io.Writer) {
+	cfg := activeConfig()
+	prev := cfg.Out
+	cfg.Out = w
 
-	return c.h.Handle(ctx, rec)
+	Init(cfg)
+	if prev != w {
+		_ = release(prev)
+	}
 }
 
-func (c *colorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &colorHandler{h: c.h.WithAttrs(attrs)}
+// own records a file opened by this package.
+func own(f io.WriteCloser) {
+	mu.Lock()
+	owned = append(owned, f)
+	mu.Unlock()
 }
 
-func (c *colorHandler) WithGroup(name string) slog.Handler {
-	return &colorHandler{h: c.h.WithGroup(name)}
+// release syncs and closes w if this package opened it.
+func release(w io.Writer) error {
+	mu.Lock()
+	i := slices.IndexFunc(owned, func(f io.WriteCloser) bool { return f == w })
+	if i < 0 {
+		mu.Unlock()
+		return nil
+	}
+	f := owned[i]
+	owned = slices.Delete(owned, i, i+1)
+	mu.Unlock()
+
+	return closeFile(f)
 }
 
-func SetLogFile(path string) error {
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
+// closeFile syncs f to disk, if it can, and closes it.
+func closeFile(f io.Closer) error {
+	var err error
+	if s, ok := f.(interface{ Sync() error }); ok {
+		err = s.Sync()
 	}
+	return errors.Join(err, f.Close())
+}
 
-	// Use the last active config if available, otherwise fall back to defaultCfg.
-	cfg := currentCfg
-	// If currentCfg is zero (not initialized), use defaultCfg.
-	if cfg.Out == nil && cfg.Level == nil {
-		cfg = defaultCfg
+// Close flushes pending records, stops the background writer of Async
+// mode and the OTLP exporter, and syncs and closes the files opened by SetLogFile,
+// SetRotatingLogFile, AddLogFile and SetAuditFile. Call it before the
+// program exits (e.g. defer logs.Close() in main).
+//
+// Logging keeps working afterwards: records are written synchronously, to
+// the remaining outputs and to os.Stderr in place of a closed Out.
+func Close() error {
+	mu.Lock()
+	q, exp := async, exporter
+	async, exporter = nil, nil
+	files := owned
+	owned = nil
+	mu.Unlock()
+	if q != nil {
+		q.close()
+	}
+	if exp != nil {
+		exp.close()
 	}
-	cfg.Out = f
 
-	// Reinitialize logger using the preserved config but with new output.
-	Init(cfg)
+	var errs []error
+	if len(files) > 0 {
+		cfg := activeConfig()
+		cfg.Async = false
+		if slices.ContainsFunc(files, func(f io.WriteCloser) bool { return f == cfg.Out }) {
+			cfg.Out = os.Stderr
+		}
+		cfg.Outputs = slices.DeleteFunc(slices.Clone(cfg.Outputs), func(o Output) bool {
+			return slices.ContainsFunc(files, func(f io.WriteCloser) bool { return f == o.Out })
+		})
+		Init(cfg)
 
-	return nil
+		for _, f := range files {
+			errs = append(errs, closeFile(f))
+		}
+	}
+
+	audit.mu.Lock()
+	if audit.closer != nil {
+		errs = append(errs, closeFile(audit.closer))
+		audit.closer, audit.out = nil, nil
+	}
+	audit.mu.Unlock()
+	return errors.Join(errs...)
 }
 
 // Init initializes the global logger.
@@ -109,25 +311,131 @@ func Init(cfg Config) {
 		cfg.Level = defaultCfg.Level
 	}
 
-	var h slog.Handler
-	if cfg.JSON {
-		h = slog.NewJSONHandler(cfg.Out, &slog.HandlerOptions{Level: cfg.Level})
-	} else {
-		base := slog.NewTextHandler(cfg.Out, &slog.HandlerOptions{Level: cfg.Level})
-		if cfg.Color {
-			h = &colorHandler{h: base}
-		} else {
-			h = base
+	opts := &slog.HandlerOptions{
+		Level:     &floorVar,
+		AddSource: cfg.AddSource,
+		ReplaceAttr: ChainReplaceAttr(
+			timeAttr(cfg.TimeFormat, cfg.TimeZone, cfg.NoTime),
+			redactor(cfg.RedactKeys, cfg.RedactPatterns),
+			choose(cfg.ExpandErrors, errorAttr),
+			cfg.ReplaceAttr,
+		),
+	}
+	// Outputs with their own Level get records below the global level
+	// too; the others are wrapped to skip them.
+	var outLevels lowestLevel
+	for _, o := range cfg.Outputs {
+		if o.Out != nil && o.Level != nil {
+			outLevels = append(outLevels, o.Level)
+		}
+	}
+	if cfg.OTLP != nil && cfg.OTLP.Level != nil {
+		outLevels = append(outLevels, cfg.OTLP.Level)
+	}
+	leveled := func(h slog.Handler, level slog.Leveler) slog.Handler {
+		if outLevels == nil {
+			return h
+		}
+		return &outputLevelHandler{h: h, level: level}
+	}
+	if outLevels != nil {
+		opts.Level = append(lowestLevel{&floorVar}, outLevels...)
+	}
+
+	h := leveled(newHandler(Output{Out: cfg.Out, JSON: cfg.JSON, Color: cfg.Color, Format: cfg.Format}, opts, cfg), nil)
+	hs := multiHandler{h}
+	for _, o := range cfg.Outputs {
+		if o.Out != nil {
+			hs = append(hs, leveled(newHandler(o, opts, cfg), o.Level))
 		}
 	}
+	var sysErr error
+	if cfg.SystemLog != NoSystemLog {
+		var sh slog.Handler
+		if sh, sysErr = newSystemHandler(cfg.SystemLog, cfg.SystemLogTag, opts); sysErr == nil {
+			hs = append(hs, leveled(sh, nil))
+		}
+	}
+	var (
+		exp     *otlpExporter
+		otlpErr error
+	)
+	if cfg.OTLP != nil {
+		local := slices.Clone(hs)
+		if exp, otlpErr = newOTLPExporter(*cfg.OTLP, local); otlpErr == nil {
+			hs = append(hs, leveled(exp.handler(opts), cfg.OTLP.Level))
+		}
+	}
+	if len(hs) > 1 {
+		h = hs
+	}
 
+	var q *asyncQueue
+	if cfg.Async {
+		q = newAsyncQueue(h, cfg.AsyncQueue)
+		h = &asyncHandler{h: h, q: q}
+	}
+	h = newStackHandler(&hookHandler{h: h}, cfg.StackTrace)
+	h = newSampleHandler(newDedupeHandler(h, cfg.Dedupe), cfg.Sampling)
+	lh := &levelHandler{h: h}
+	if outLevels != nil {
+		lh.outputs = outLevels
+	}
+	h = lh
+
+	ring := historyRing(cfg.History)
+	if ring != nil {
+		h = newHistoryHandler(h, ring, cfg, opts)
+	}
 	l := slog.New(h)
 
 	mu.Lock()
+	history = ring
+	levelVar.Set(cfg.Level.Level())
+	updateFloor()
+	callerSkip.Store(int32(cfg.CallerSkip))
 	logger = l
 	// Remember the active config for future SetLogFile calls
 	currentCfg = cfg
+	generation.Add(1)
+	prevAsync, prevExp := async, exporter
+	async, exporter = q, exp
 	mu.Unlock()
+
+	if prevAsync != nil {
+		prevAsync.close()
+	}
+	if prevExp != nil {
+		prevExp.close()
+	}
+
+	if sysErr != nil {
+		l.Warn("System log unavailable", "system_log", cfg.SystemLog.String(), "err", sysErr)
+	}
+	if otlpErr != nil {
+		l.Warn("OTLP log export disabled", "err", otlpErr)
+	}
+}
+
+// newHandler builds the handler for one output's format.
+func newHandler(o Output, opts *slog.HandlerOptions, cfg Config) slog.Handler {
+	switch format(o.Format, o.JSON) {
+	case FormatJSON:
+		return slog.NewJSONHandler(o.Out, opts)
+	case FormatLogfmt:
+		return newLogfmtHandler(o.Out, opts)
+	case FormatPretty:
+		colors, _ := colorScheme(o, cfg.Colors)
+		return newPrettyHandler(o.Out, colors, opts)
+	case FormatGCP:
+		return newGCPHandler(o.Out, opts, cfg.Cloud)
+	case FormatCloudWatch:
+		return newCloudWatchHandler(o.Out, opts, cfg.Cloud)
+	}
+	if colors, ok := colorScheme(o, cfg.Colors); ok {
+		return newColorHandler(o.Out, colors, opts)
+	}
+	return slog.NewTextHandler(o.Out, opts)
 }
 
 // get returns the current global logger, lazily initialized.
@@ -159,33 +467,69 @@ func WithGroup(name string) *slog.Logger {
 // --- Helper functions for convenience ---
 
 func Debug(msg string, args ...any) {
-	get().Debug(msg, args...)
+	emit(context.Background(), slog.LevelDebug, msg, args...)
 }
 
 func Info(msg string, args ...any) {
-	get().Info(msg, args...)
+	emit(context.Background(), slog.LevelInfo, msg, args...)
 }
 
 func Warn(msg string, args ...any) {
-	get().Warn(msg, args...)
+	emit(context.Background(), slog.LevelWarn, msg, args...)
 }
 
 func Error(msg string, args ...any) {
-	get().Error(msg, args...)
+	emit(context.Background(), slog.LevelError, msg, args...)
 }
 
 func DebugCtx(ctx context.Context, msg string, args ...any) {
-	get().DebugContext(ctx, msg, args...)
+	emit(ctx, slog.LevelDebug, msg, args...)
 }
 
 func InfoCtx(ctx context.Context, msg string, args ...any) {
-	get().InfoContext(ctx, msg, args...)
+	emit(ctx, slog.LevelInfo, msg, args...)
 }
 
 func WarnCtx(ctx context.Context, msg string, args ...any) {
-	get().WarnContext(ctx, msg, args...)
+	emit(ctx, slog.LevelWarn, msg, args...)
 }
 
 func ErrorCtx(ctx context.Context, msg string, args ...any) {
-	get().ErrorContext(ctx, msg, args...)
+	emit(ctx, slog.LevelError, msg, args...)
+}
+
+// emit logs through the global logger, recording the helper's caller as
+// the source location rather than this package.
+func emit(ctx context.Context, level slog.Level, msg string, args ...any) {
+	l := get()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), level, msg, callerPC(3)) // skip [Callers, emit, helper]
+	r.AddAttrs(traceAttrs(ctx)...)
+	r.AddAttrs(contextAttrs(ctx)...)
+	r.Add(args...)
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// SetHandler replaces the global logger's handler with h, bypassing Config
+// (outputs, levels, redaction, ... no longer apply). It returns a function
+// that restores the previous logger. Intended for tests (see logstest) and
+// for programs that bring their own slog.Handler.
+func SetHandler(h slog.Handler) (restore func()) {
+	prev := get()
+	mu.Lock()
+	logger = slog.New(h)
+	generation.Add(1)
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		logger = prev
+		generation.Add(1)
+		mu.Unlock()
+	}
 }
@@ -0,0 +1,169 @@
+// Package logstest captures records of the logs package in tests, so
+// assertions don't depend on output formatting.
+package logstest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/toobprojects/go-commons/logs"
+)
+
+// Record is a captured log record. Attrs holds every attribute, including
+// ones from With, with nested groups flattened to dotted keys ("req.id").
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+// Recorder collects records while a Capture is active.
+type Recorder struct {
+	t       testing.TB
+	mu      sync.Mutex
+	records []Record
+}
+
+// Capture routes all records of the logs package (every level, debug
+// included) to a new Recorder instead of the configured outputs, until the
+// test ends. The logger is global, so don't use it in parallel tests.
+func Capture(t testing.TB) *Recorder {
+	t.Helper()
+	r := &Recorder{t: t}
+	restore := logs.SetHandler(&handler{rec: r})
+	t.Cleanup(restore)
+	return r
+}
+
+// Records returns the records captured so far.
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+// Reset discards the captured records.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.records = nil
+	r.mu.Unlock()
+}
+
+// Find returns the first record at level whose message contains
+// msgContains and that has all given attributes (alternating key/value
+// pairs; values compare equal, or by their fmt.Sprint form).
+func (r *Recorder) Find(level slog.Level, msgContains string, attrs ...any) (Record, bool) {
+	for _, rec := range r.Records() {
+		if rec.Level == level && strings.Contains(rec.Message, msgContains) && hasAttrs(rec, attrs) {
+			return rec, true
+		}
+	}
+	return Record{}, false
+}
+
+// AssertLogged fails the test unless a matching record was captured (see
+// Find).
+func (r *Recorder) AssertLogged(level slog.Level, msgContains string, attrs ...any) {
+	r.t.Helper()
+	if _, ok := r.Find(level, msgContains, attrs...); !ok {
+		r.t.Errorf("no %s record containing %q with %v; captured:\n%s", level, msgContains, attrs, r.String())
+	}
+}
+
+// AssertNotLogged fails the test if a matching record was captured.
+func (r *Recorder) AssertNotLogged(level slog.Level, msgContains string, attrs ...any) {
+	r.t.Helper()
+	if rec, ok := r.Find(level, msgContains, attrs...); ok {
+		r.t.Errorf("unexpected %s record %q %v", rec.Level, rec.Message, rec.Attrs)
+	}
+}
+
+// String lists the captured records, one per line, for failure messages.
+func (r *Recorder) String() string {
+	var b strings.Builder
+	for _, rec := range r.Records() {
+		fmt.Fprintf(&b, "  %s %q %v\n", rec.Level, rec.Message, rec.Attrs)
+	}
+	return b.String()
+}
+
+func hasAttrs(rec Record, attrs []any) bool {
+	for i := 0; i+1 < len(attrs); i += 2 {
+		key := fmt.Sprint(attrs[i])
+		got, ok := rec.Attrs[key]
+		if !ok {
+			return false
+		}
+		want := attrs[i+1]
+		if !reflect.DeepEqual(got, want) && fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// handler records into a Recorder, flattening groups.
+type handler struct {
+	rec    *Recorder
+	prefix string
+	attrs  map[string]any
+}
+
+func (h *handler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	rec := Record{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: map[string]any{}}
+	for k, v := range h.attrs {
+		rec.Attrs[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flatten(rec.Attrs, h.prefix, a)
+		return true
+	})
+
+	h.rec.mu.Lock()
+	h.rec.records = append(h.rec.records, rec)
+	h.rec.mu.Unlock()
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := &handler{rec: h.rec, prefix: h.prefix, attrs: map[string]any{}}
+	for k, v := range h.attrs {
+		c.attrs[k] = v
+	}
+	for _, a := range attrs {
+		flatten(c.attrs, h.prefix, a)
+	}
+	return c
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &handler{rec: h.rec, prefix: h.prefix + name + ".", attrs: h.attrs}
+}
+
+func flatten(m map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			flatten(m, prefix, ga)
+		}
+		return
+	}
+	if a.Key != "" {
+		m[prefix+a.Key] = v.Any()
+	}
+}
@@ -0,0 +1,99 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// multiHandler sends each record to several handlers (Config.Outputs).
+type multiHandler []slog.Handler
+
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m {
+		if h.Enabled(ctx, r.Level) {
+			// Each handler gets its own copy; handlers may modify records.
+			if err := h.Handle(ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	out := make(multiHandler, len(m))
+	for i, h := range m {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
+
+// belowGlobalKey marks, in the context passed down the handler chain, a
+// record that didn't pass the global level and is only handled because
+// an output with its own Level may want it.
+type belowGlobalKey struct{}
+
+func belowGlobal(ctx context.Context) bool {
+	return ctx.Value(belowGlobalKey{}) != nil
+}
+
+// outputLevelHandler applies Output.Level: with a level, records at or
+// above it pass regardless of the global level; without one, only records
+// that passed the global level do.
+type outputLevelHandler struct {
+	h     slog.Handler
+	level slog.Leveler // nil = follow the global level
+}
+
+func (o *outputLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if o.level != nil && level < o.level.Level() {
+		return false
+	}
+	return o.h.Enabled(ctx, level)
+}
+
+func (o *outputLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if o.level == nil && belowGlobal(ctx) {
+		return nil
+	}
+	return o.h.Handle(ctx, r)
+}
+
+func (o *outputLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &outputLevelHandler{h: o.h.WithAttrs(attrs), level: o.level}
+}
+
+func (o *outputLevelHandler) WithGroup(name string) slog.Handler {
+	return &outputLevelHandler{h: o.h.WithGroup(name), level: o.level}
+}
+
+// lowestLevel is the lowest of several levels, evaluated on each call so
+// a *slog.LevelVar among them can change at runtime.
+type lowestLevel []slog.Leveler
+
+func (l lowestLevel) Level() slog.Level {
+	lowest := l[0].Level()
+	for _, v := range l[1:] {
+		lowest = min(lowest, v.Level())
+	}
+	return lowest
+}
@@ -0,0 +1,141 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// LoggerKey is the attribute that carries a Named logger's name.
+const LoggerKey = "logger"
+
+// generation changes whenever the global handler is replaced, so Named
+// loggers pick up the new configuration.
+var generation atomic.Uint64
+
+var named struct {
+	mu      sync.Mutex
+	loggers map[string]*slog.Logger
+}
+
+// LoggerInfo describes a logger created with Named.
+type LoggerInfo struct {
+	Name     string
+	Level    slog.Level // effective minimum level
+	Override bool       // Level was set with SetLoggerLevel (for this name or a parent)
+}
+
+// Named returns the logger for name, creating it on first use; later calls
+// return the same logger. Records carry a logger=name attribute and its
+// level can be set independently with SetLoggerLevel. Dotted names form a
+// hierarchy: "app.db" follows the level of "app" unless it has its own.
+//
+// Unlike With, named loggers follow Init / SetLogFile: they always write
+// through the current configuration.
+func Named(name string) *slog.Logger {
+	named.mu.Lock()
+	defer named.mu.Unlock()
+
+	if l, ok := named.loggers[name]; ok {
+		return l
+	}
+	if named.loggers == nil {
+		named.loggers = map[string]*slog.Logger{}
+	}
+	l := slog.New(&namedHandler{name: name, cache: &atomic.Pointer[namedCache]{}})
+	named.loggers[name] = l
+	return l
+}
+
+// SetLoggerLevel sets the minimum level of the Named logger name and its
+// descendants ("app" also covers "app.db"; the most specific setting
+// wins), independently of the global level.
+func SetLoggerLevel(name string, level slog.Level) {
+	loggerLevels.set(name, level)
+}
+
+// ClearLoggerLevel removes the level set for name.
+func ClearLoggerLevel(name string) {
+	loggerLevels.clear(name)
+}
+
+// Loggers lists the Named loggers created so far, sorted by name.
+func Loggers() []LoggerInfo {
+	named.mu.Lock()
+	names := make([]string, 0, len(named.loggers))
+	for name := range named.loggers {
+		names = append(names, name)
+	}
+	named.mu.Unlock()
+	slices.Sort(names)
+
+	out := make([]LoggerInfo, len(names))
+	for i, name := range names {
+		lvl, ok := loggerLevels.lookup(name)
+		if !ok {
+			lvl = Level()
+		}
+		out[i] = LoggerInfo{Name: name, Level: lvl, Override: ok}
+	}
+	return out
+}
+
+// namedHandler derives its handler from the current global one, rebuilding
+// it after Init. With / WithGroup calls are recorded and replayed.
+type namedHandler struct {
+	name  string
+	ops   []func(slog.Handler) slog.Handler
+	cache *atomic.Pointer[namedCache]
+}
+
+type namedCache struct {
+	gen uint64
+	h   slog.Handler
+}
+
+func (n *namedHandler) handler() slog.Handler {
+	root := get() // may Init lazily, which bumps the generation
+	gen := generation.Load()
+	if c := n.cache.Load(); c != nil && c.gen == gen {
+		return c.h
+	}
+
+	h := root.Handler()
+	if n.name != "" { // "" follows the global logger itself (RedirectStdLog)
+		if nh, ok := h.(interface{ withName(string) slog.Handler }); ok {
+			h = nh.withName(n.name)
+		}
+		h = h.WithAttrs([]slog.Attr{slog.String(LoggerKey, n.name)})
+	}
+	for _, op := range n.ops {
+		h = op(h)
+	}
+	n.cache.Store(&namedCache{gen: gen, h: h})
+	return h
+}
+
+func (n *namedHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return n.handler().Enabled(ctx, level)
+}
+
+func (n *namedHandler) Handle(ctx context.Context, r slog.Record) error {
+	return n.handler().Handle(ctx, r)
+}
+
+func (n *namedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return n.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (n *namedHandler) WithGroup(name string) slog.Handler {
+	return n.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+func (n *namedHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	return &namedHandler{
+		name:  n.name,
+		ops:   append(n.ops[:len(n.ops):len(n.ops)], op),
+		cache: &atomic.Pointer[namedCache]{},
+	}
+}
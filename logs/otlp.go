@@ -0,0 +1,329 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// OTLP configures export to an OpenTelemetry collector (Config.OTLP).
+// Records are sent with OTLP/HTTP in its JSON encoding, which needs no
+// dependencies; gRPC is not supported.
+type OTLP struct {
+	// Endpoint is the collector's OTLP/HTTP address, e.g.
+	// "http://localhost:4318"; "/v1/logs" is added when there is no path.
+	Endpoint string
+
+	// Headers are added to each request, e.g. for authentication.
+	Headers map[string]string
+
+	// ServiceName is the service.name resource attribute (default: the
+	// executable name); Resource holds more resource attributes.
+	ServiceName string
+	Resource    map[string]string
+
+	// Level is the minimum level exported (nil = the global level), as
+	// Output.Level.
+	Level slog.Leveler
+
+	BatchSize     int           // records per request (default 512)
+	FlushInterval time.Duration // send a partial batch after this long (default 1s)
+	QueueSize     int           // records waiting to be sent; more are dropped (default 8192)
+	MaxRetries    int           // retries of a failed request, with exponential backoff (default 5)
+	Timeout       time.Duration // per request (default 10s)
+
+	// Client sends the requests (default: a client with Timeout).
+	Client *http.Client
+}
+
+const otlpScope = "github.com/toobprojects/go-commons/logs"
+
+// otlpExporter batches records and posts them from a background goroutine.
+type otlpExporter struct {
+	cfg      OTLP
+	url      string
+	resource []otlpKeyValue
+	report   slog.Handler // local outputs, for export failures
+
+	records chan otlpRecord
+	flushes chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 any            `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+func newOTLPExporter(cfg OTLP, report slog.Handler) (*otlpExporter, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q", cfg.Endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/logs"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 512
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 8192
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 5
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	}
+
+	resource := []otlpKeyValue{{Key: "service.name", Value: otlpString(cfg.ServiceName)}}
+	for _, k := range slices.Sorted(maps.Keys(cfg.Resource)) {
+		resource = append(resource, otlpKeyValue{Key: k, Value: otlpString(cfg.Resource[k])})
+	}
+
+	e := &otlpExporter{
+		cfg:      cfg,
+		url:      u.String(),
+		resource: resource,
+		report:   report,
+		records:  make(chan otlpRecord, cfg.QueueSize),
+		flushes:  make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+// handler returns the slog.Handler that feeds the exporter.
+func (e *otlpExporter) handler(opts *slog.HandlerOptions) slog.Handler {
+	return newFieldHandler(opts, func(r slog.Record, msg slog.Attr, fields []field) error {
+		rec := otlpRecord{
+			TimeUnixNano:         strconv.FormatInt(r.Time.UnixNano(), 10),
+			ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+			SeverityNumber:       min(max(int(r.Level)+9, 1), 24), // DEBUG 5, INFO 9, WARN 13, ERROR 17
+			SeverityText:         r.Level.String(),
+			Body:                 otlpString(msg.Value.String()),
+		}
+		for _, f := range fields {
+			switch s := f.value.String(); {
+			case f.key == "trace_id" && isHex(s, 32):
+				rec.TraceID = s
+			case f.key == "span_id" && isHex(s, 16):
+				rec.SpanID = s
+			default:
+				rec.Attributes = append(rec.Attributes, otlpKeyValue{Key: f.key, Value: otlpValue(f.value)})
+			}
+		}
+
+		select {
+		case e.records <- rec:
+		default:
+			e.dropped.Add(1)
+		}
+		return nil
+	})
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []otlpRecord
+	send := func() {
+		if len(batch) > 0 {
+			e.send(batch)
+			batch = nil
+		}
+	}
+	drain := func() {
+		for {
+			select {
+			case rec := <-e.records:
+				batch = append(batch, rec)
+				if len(batch) >= e.cfg.BatchSize {
+					send()
+				}
+			default:
+				send()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case rec := <-e.records:
+			batch = append(batch, rec)
+			if len(batch) >= e.cfg.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ch := <-e.flushes:
+			drain()
+			close(ch)
+		case <-e.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// send posts a batch, retrying with exponential backoff; after the last
+// attempt (or on a permanent error) the batch is dropped and reported.
+func (e *otlpExporter) send(batch []otlpRecord) {
+	body, err := json.Marshal(map[string]any{
+		"resourceLogs": []any{map[string]any{
+			"resource": map[string]any{"attributes": e.resource},
+			"scopeLogs": []any{map[string]any{
+				"scope":      map[string]any{"name": otlpScope},
+				"logRecords": batch,
+			}},
+		}},
+	})
+	if err == nil {
+		backoff := 500 * time.Millisecond
+		for attempt := 0; ; attempt++ {
+			var retry bool
+			if retry, err = e.post(body); err == nil || !retry || attempt >= e.cfg.MaxRetries {
+				break
+			}
+			select {
+			case <-time.After(backoff):
+				backoff = min(2*backoff, 30*time.Second)
+			case <-e.stop:
+				attempt = e.cfg.MaxRetries // closing: one last try
+			}
+		}
+	}
+
+	if err != nil || e.dropped.Load() > 0 {
+		r := slog.NewRecord(time.Now(), slog.LevelWarn, "OTLP log export failed", 0)
+		if err != nil {
+			r.AddAttrs(slog.Int("records", len(batch)), slog.Any("err", err))
+		}
+		if n := e.dropped.Swap(0); n > 0 {
+			r.AddAttrs(slog.Int64("dropped", n))
+		}
+		_ = e.report.Handle(context.Background(), r)
+	}
+}
+
+// post sends one request; retry reports whether a failure is temporary.
+func (e *otlpExporter) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.cfg.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode/100 == 2:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+		return true, errors.New("OTLP collector: " + resp.Status)
+	}
+	return false, errors.New("OTLP collector: " + resp.Status)
+}
+
+// flush blocks until queued records have been sent.
+func (e *otlpExporter) flush() {
+	ch := make(chan struct{})
+	select {
+	case e.flushes <- ch:
+		<-ch
+	case <-e.done:
+	}
+}
+
+// close sends the remaining records and stops the exporter.
+func (e *otlpExporter) close() {
+	select {
+	case <-e.stop:
+	default:
+		close(e.stop)
+	}
+	<-e.done
+}
+
+func otlpString(s string) map[string]any {
+	return map[string]any{"stringValue": s}
+}
+
+// otlpValue converts a slog value to an OTLP AnyValue.
+func otlpValue(v slog.Value) map[string]any {
+	switch v.Kind() {
+	case slog.KindBool:
+		return map[string]any{"boolValue": v.Bool()}
+	case slog.KindInt64:
+		return map[string]any{"intValue": strconv.FormatInt(v.Int64(), 10)}
+	case slog.KindUint64:
+		return map[string]any{"intValue": strconv.FormatUint(v.Uint64(), 10)}
+	case slog.KindFloat64:
+		return map[string]any{"doubleValue": v.Float64()}
+	case slog.KindTime:
+		return otlpString(v.Time().Format(time.RFC3339Nano))
+	case slog.KindAny:
+		if b, ok := v.Any().([]byte); ok {
+			return map[string]any{"bytesValue": b} // base64, as OTLP/JSON expects
+		}
+	}
+	return otlpString(v.String())
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
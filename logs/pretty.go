@@ -0,0 +1,109 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const colorBold = "\033[1m"
+
+// newPrettyHandler renders records for humans during development: a time,
+// a level badge and the message on one line, then one aligned "key: value"
+// line per attribute, with maps, slices and structs pretty-printed as
+// indented JSON:
+//
+//	13:04:05.123 ERR upload failed
+//	    req.id : 42
+//	    err    : connection reset
+//	    meta   : {
+//	               "retries": 3
+//	             }
+//
+// The level badge, keys and time are painted with colors (the zero
+// ColorScheme for plain output).
+func newPrettyHandler(w io.Writer, colors ColorScheme, opts *slog.HandlerOptions) slog.Handler {
+	var mu sync.Mutex
+
+	return newFieldHandler(opts, func(r slog.Record, msg slog.Attr, fields []field) error {
+		var b strings.Builder
+		if ts := prettyTime(r.Time, opts); ts != "" {
+			b.WriteString(paint(colors.Time, ts) + " ")
+		}
+		c := colors.level(r.Level)
+		if c != "" {
+			c += colorBold
+		}
+		b.WriteString(paint(c, levelBadge(r.Level)) + " " + msg.Value.String() + "\n")
+
+		width := 0
+		for _, f := range fields {
+			width = max(width, len(f.key))
+		}
+		indent := strings.Repeat(" ", 4+width+3)
+		for _, f := range fields {
+			b.WriteString("    " + paint(colors.Key, f.key) + strings.Repeat(" ", width-len(f.key)) + " : ")
+			b.WriteString(strings.ReplaceAll(prettyValue(f.value), "\n", "\n"+indent))
+			b.WriteByte('\n')
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		_, err := io.WriteString(w, b.String())
+		return err
+	})
+}
+
+// prettyTime renders the timestamp as 15:04:05.000, or as configured
+// through ReplaceAttr (Config.TimeFormat, NoTime).
+func prettyTime(t time.Time, opts *slog.HandlerOptions) string {
+	if t.IsZero() {
+		return ""
+	}
+	if opts == nil || opts.ReplaceAttr == nil {
+		return t.Format("15:04:05.000")
+	}
+	a := opts.ReplaceAttr(nil, slog.Time(slog.TimeKey, t))
+	switch {
+	case a.Key == "":
+		return ""
+	case a.Value.Kind() == slog.KindTime:
+		return a.Value.Time().Format("15:04:05.000")
+	}
+	return a.Value.String()
+}
+
+// levelBadge returns a short level label.
+func levelBadge(l slog.Level) string {
+	switch {
+	case l >= slog.LevelError:
+		return "ERR"
+	case l >= slog.LevelWarn:
+		return "WRN"
+	case l >= slog.LevelInfo:
+		return "INF"
+	default:
+		return "DBG"
+	}
+}
+
+// prettyValue renders composite values as indented JSON and everything
+// else with slog's default formatting.
+func prettyValue(v slog.Value) string {
+	if v.Kind() != slog.KindAny {
+		return v.String()
+	}
+	switch v.Any().(type) {
+	case error, fmt.Stringer:
+		return v.String()
+	}
+	out, err := json.MarshalIndent(v.Any(), "", "  ")
+	if err != nil || len(out) == 0 || (out[0] != '{' && out[0] != '[') {
+		return v.String()
+	}
+	return string(out)
+}
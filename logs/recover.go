@@ -0,0 +1,118 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type recoverOptions struct {
+	repanic bool // panic again after logging
+}
+
+// RecoverOption configures RecoverAndLog and Go.
+type RecoverOption func(*recoverOptions)
+
+// WithRepanic panics again with the original value after logging, so the
+// program still crashes (with the record already written).
+func WithRepanic() RecoverOption {
+	return func(o *recoverOptions) { o.repanic = true }
+}
+
+// RecoverAndLog recovers a panic and logs it at error level as "Recovered
+// panic" with the panic value and the panicking goroutine's stack as
+// structured attributes (panic, stack), plus ctx's fields like the *Ctx
+// helpers. The source is the line that panicked. It must be deferred
+// directly:
+//
+//	defer logs.RecoverAndLog(ctx)
+//
+// By default the panic is swallowed; WithRepanic re-raises it.
+func RecoverAndLog(ctx context.Context, opts ...RecoverOption) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	logPanic(ctx, v)
+	if o.repanic {
+		Flush()
+		panic(v)
+	}
+}
+
+// Go runs fn in a new goroutine that logs a panic instead of letting it
+// crash the program with unstructured output (see RecoverAndLog).
+//
+//	logs.Go(func() { worker(jobs) })
+func Go(fn func(), opts ...RecoverOption) {
+	go func() {
+		defer RecoverAndLog(context.Background(), opts...)
+		fn()
+	}()
+}
+
+func logPanic(ctx context.Context, v any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	l := get()
+	if !l.Enabled(ctx, slog.LevelError) {
+		return
+	}
+	depth := activeConfig().StackTrace.Depth
+	if depth <= 0 {
+		depth = defaultStackDepth
+	}
+	pc, stack := panicStack(depth)
+	r := slog.NewRecord(time.Now(), slog.LevelError, "Recovered panic", pc)
+	r.AddAttrs(traceAttrs(ctx)...)
+	r.AddAttrs(contextAttrs(ctx)...)
+	r.AddAttrs(slog.Any("panic", v), slog.Any("stack", stack))
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// panicStack returns the PC of the code that panicked and up to depth
+// "function file:line" frames from there. It must be called from the
+// deferred function, while the panicking frames are still on the stack.
+func panicStack(depth int) (uintptr, []string) {
+	pcs := make([]uintptr, depth+64)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+
+	// Skip to runtime.gopanic, then past the runtime frames that raised
+	// the panic (e.g. runtime.panicmem for a nil dereference).
+	start := len(pcs)
+	for i, pc := range pcs {
+		if fn := runtime.FuncForPC(pc); fn != nil && fn.Name() == "runtime.gopanic" {
+			start = i + 1
+			break
+		}
+	}
+	for start < len(pcs) {
+		fn := runtime.FuncForPC(pcs[start])
+		if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+			break
+		}
+		start++
+	}
+	if start == len(pcs) {
+		return 0, nil
+	}
+
+	frames := runtime.CallersFrames(pcs[start:])
+	out := make([]string, 0, depth)
+	for len(out) < depth {
+		f, more := frames.Next()
+		out = append(out, f.Function+" "+f.File+":"+strconv.Itoa(f.Line))
+		if !more {
+			break
+		}
+	}
+	return pcs[start], out
+}
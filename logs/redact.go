@@ -0,0 +1,71 @@
+package logs
+
+import (
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces masked values in log output.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are common secret attribute names, a starting point for
+// Config.RedactKeys.
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token",
+	"api_key", "apikey", "authorization", "cookie", "set-cookie", "private_key",
+}
+
+// redactor returns a slog ReplaceAttr function that masks attributes named
+// in keys (case-insensitive; a matching group masks everything in it) and
+// matches of patterns in string values and messages. It returns nil when
+// there is nothing to redact.
+func redactor(keys []string, patterns []*regexp.Regexp) func([]string, slog.Attr) slog.Attr {
+	if len(keys) == 0 && len(patterns) == 0 {
+		return nil
+	}
+	lower := make([]string, len(keys))
+	for i, k := range keys {
+		lower[i] = strings.ToLower(k)
+	}
+	isSecret := func(key string) bool {
+		return slices.Contains(lower, strings.ToLower(key))
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 {
+			switch a.Key {
+			case slog.TimeKey, slog.LevelKey, slog.SourceKey:
+				return a
+			case slog.MessageKey:
+				return slog.String(a.Key, redactString(a.Value.String(), patterns))
+			}
+		}
+		if isSecret(a.Key) || slices.ContainsFunc(groups, isSecret) {
+			return slog.String(a.Key, Redacted)
+		}
+		if len(patterns) == 0 {
+			return a
+		}
+
+		switch a.Value.Kind() {
+		case slog.KindString:
+			return slog.String(a.Key, redactString(a.Value.String(), patterns))
+		case slog.KindAny:
+			// Errors and other values are masked in their rendered form.
+			s := a.Value.String()
+			if r := redactString(s, patterns); r != s {
+				return slog.String(a.Key, r)
+			}
+		}
+		return a
+	}
+}
+
+func redactString(s string, patterns []*regexp.Regexp) string {
+	for _, re := range patterns {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package logs
+
+import "log/slog"
+
+// ChainReplaceAttr combines ReplaceAttr functions: they run in order (nil
+// entries are skipped), and an attribute dropped by one (empty key) is not
+// passed to the next.
+func ChainReplaceAttr(fns ...func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	var list []func([]string, slog.Attr) slog.Attr
+	for _, fn := range fns {
+		if fn != nil {
+			list = append(list, fn)
+		}
+	}
+	switch len(list) {
+	case 0:
+		return nil
+	case 1:
+		return list[0]
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, fn := range list {
+			a = fn(groups, a)
+			if a.Key == "" {
+				return a
+			}
+		}
+		return a
+	}
+}
+
+// RenameKeys returns a ReplaceAttr function that renames attribute keys,
+// including the built-in ones, e.g. for ECS field names:
+//
+//	logs.RenameKeys(map[string]string{
+//		slog.TimeKey:    "@timestamp",
+//		slog.LevelKey:   "log.level",
+//		slog.MessageKey: "message",
+//	})
+//
+// Keys inside groups match by their own name.
+func RenameKeys(names map[string]string) func([]string, slog.Attr) slog.Attr {
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if to, ok := names[a.Key]; ok {
+			a.Key = to
+		}
+		return a
+	}
+}
@@ -0,0 +1,240 @@
+package logs
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp inserted into rotated file names,
+// e.g. app-2024-05-01T13-04-05.000.log.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Rotation controls when a RotatingFile rolls over and which old files it
+// keeps. The zero value never rotates and keeps everything.
+type Rotation struct {
+	MaxSize    int64         // rotate before a write would exceed this many bytes (0 = never)
+	MaxBackups int           // keep at most this many rotated files (0 = all)
+	MaxAge     time.Duration // delete rotated files older than this (0 = never)
+	Compress   bool          // gzip rotated files
+}
+
+// RotatingFile is an append-only log file that rotates itself according to
+// a Rotation. Rotated files are renamed to name-<timestamp>.ext next to the
+// active file; compression and cleanup run in the background.
+//
+// It is safe for concurrent use and can be used directly as Config.Out.
+type RotatingFile struct {
+	mu   sync.Mutex
+	path string
+	opts Rotation
+	f    *os.File
+	size int64
+	last time.Time // timestamp of the newest backup, to keep names unique
+
+	millMu sync.Mutex // serializes compression / cleanup
+}
+
+// OpenRotatingFile opens (or creates) path for appending with the given
+// rotation policy.
+func OpenRotatingFile(path string, opts Rotation) (*RotatingFile, error) {
+	w := &RotatingFile{path: path, opts: opts}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFile) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.f, w.size = f, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would push the file past MaxSize.
+// A single write larger than MaxSize still goes to a fresh file intact.
+func (w *RotatingFile) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	if w.opts.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.opts.MaxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate rolls the file over now, regardless of its size (e.g. from a
+// SIGHUP handler or a daily timer).
+func (w *RotatingFile) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Sync commits the active file to stable storage.
+func (w *RotatingFile) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return os.ErrClosed
+	}
+	return w.f.Sync()
+}
+
+// Close closes the active file. Later writes fail with os.ErrClosed.
+func (w *RotatingFile) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// rotate renames the active file to a backup and opens a new one. Callers
+// hold mu.
+func (w *RotatingFile) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("rotate %s: %w", w.path, err)
+	}
+	w.f = nil
+
+	now := time.Now().Truncate(time.Millisecond)
+	if !now.After(w.last) {
+		now = w.last.Add(time.Millisecond)
+	}
+	w.last = now
+	prefix, ext := w.backupParts()
+	backup := prefix + now.Format(backupTimeFormat) + ext
+	if err := os.Rename(w.path, backup); err != nil && !os.IsNotExist(err) {
+		// Keep logging to the old file rather than losing output.
+		_ = w.open()
+		return fmt.Errorf("rotate %s: %w", w.path, err)
+	}
+	if err := w.open(); err != nil {
+		return fmt.Errorf("rotate %s: %w", w.path, err)
+	}
+
+	go w.mill(backup)
+	return nil
+}
+
+// backupParts splits the active path into the backup name prefix
+// ("dir/app-") and extension (".log").
+func (w *RotatingFile) backupParts() (string, string) {
+	ext := filepath.Ext(w.path)
+	return strings.TrimSuffix(w.path, ext) + "-", ext
+}
+
+// mill compresses the new backup and removes backups beyond MaxBackups or
+// older than MaxAge. Errors are ignored: logging must not fail because of
+// housekeeping.
+func (w *RotatingFile) mill(backup string) {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	if w.opts.Compress {
+		if err := gzipFile(backup); err == nil {
+			_ = os.Remove(backup)
+		}
+	}
+	if w.opts.MaxBackups <= 0 && w.opts.MaxAge <= 0 {
+		return
+	}
+
+	backups := w.backups()
+	cutoff := time.Now().Add(-w.opts.MaxAge)
+	for i, b := range backups {
+		tooMany := w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups
+		tooOld := w.opts.MaxAge > 0 && b.t.Before(cutoff)
+		if tooMany || tooOld {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+type backupFile struct {
+	path string
+	t    time.Time
+}
+
+// backups lists rotated files of w, newest first.
+func (w *RotatingFile) backups() []backupFile {
+	prefix, ext := w.backupParts()
+	entries, err := os.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return nil
+	}
+
+	base := filepath.Base(prefix)
+	var out []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, base) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name[len(base):], ".gz"), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		out = append(out, backupFile{path: filepath.Join(filepath.Dir(w.path), name), t: t})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].t.After(out[j].t) })
+	return out
+}
+
+// gzipFile writes path+".gz".
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	return out.Close()
+}
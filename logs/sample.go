@@ -0,0 +1,147 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// maxSampleKeys bounds the sampler's memory; idle keys are pruned beyond it.
+const maxSampleKeys = 4096
+
+// Sampling limits how often identical messages are logged, so a tight
+// retry loop can't flood the output. Records are identical when they have
+// the same level, group and message; attributes are not compared.
+type Sampling struct {
+	// First is how many identical records pass per window. 0 disables
+	// sampling.
+	First int
+
+	// Per is the window length (default: one second).
+	Per time.Duration
+}
+
+// sampleHandler drops records beyond Sampling.First per window and logs a
+// summary of how many were suppressed once the window ends.
+type sampleHandler struct {
+	h     slog.Handler
+	s     *sampler
+	group string
+}
+
+type sampler struct {
+	cfg     Sampling
+	mu      sync.Mutex
+	entries map[sampleKey]*sampleEntry
+}
+
+type sampleKey struct {
+	level slog.Level
+	group string
+	msg   string
+}
+
+type sampleEntry struct {
+	start      time.Time
+	count      int
+	suppressed int
+	h          slog.Handler // for the summary
+	timer      *time.Timer
+}
+
+func newSampleHandler(h slog.Handler, cfg Sampling) slog.Handler {
+	if cfg.First <= 0 {
+		return h
+	}
+	if cfg.Per <= 0 {
+		cfg.Per = time.Second
+	}
+	return &sampleHandler{h: h, s: &sampler{cfg: cfg, entries: map[sampleKey]*sampleEntry{}}}
+}
+
+func (s *sampleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.h.Enabled(ctx, level)
+}
+
+func (s *sampleHandler) Handle(ctx context.Context, r slog.Record) error {
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if !s.s.allow(sampleKey{level: r.Level, group: s.group, msg: r.Message}, s.h, now) {
+		return nil
+	}
+	return s.h.Handle(ctx, r)
+}
+
+func (s *sampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sampleHandler{h: s.h.WithAttrs(attrs), s: s.s, group: s.group}
+}
+
+func (s *sampleHandler) WithGroup(name string) slog.Handler {
+	group := name
+	if s.group != "" {
+		group = s.group + "." + name
+	}
+	return &sampleHandler{h: s.h.WithGroup(name), s: s.s, group: group}
+}
+
+// allow counts a record and reports whether it should be logged.
+func (s *sampler) allow(key sampleKey, h slog.Handler, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.entries[key]
+	if e == nil || now.Sub(e.start) >= s.cfg.Per {
+		if e != nil && e.timer != nil && e.timer.Stop() {
+			// The window ended without its timer firing yet.
+			s.summarize(key, e)
+		}
+		if len(s.entries) >= maxSampleKeys {
+			s.prune(now)
+		}
+		e = &sampleEntry{start: now, h: h}
+		s.entries[key] = e
+	}
+
+	e.count++
+	if e.count <= s.cfg.First {
+		return true
+	}
+	e.suppressed++
+	if e.timer == nil {
+		// Report the suppressed records when the window closes, even if
+		// the message never comes again.
+		e.timer = time.AfterFunc(e.start.Add(s.cfg.Per).Sub(now), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.summarize(key, e)
+		})
+	}
+	return false
+}
+
+// summarize logs how many records of e were dropped. Callers hold mu.
+func (s *sampler) summarize(key sampleKey, e *sampleEntry) {
+	if e.suppressed == 0 {
+		return
+	}
+	r := slog.NewRecord(time.Now(), key.level, "Suppressed duplicate log messages", 0)
+	r.AddAttrs(
+		slog.String("message", key.msg),
+		slog.Int("suppressed", e.suppressed),
+		slog.Duration("window", s.cfg.Per),
+	)
+	e.suppressed = 0
+	_ = e.h.Handle(context.Background(), r)
+}
+
+// prune drops entries whose window is over. Callers hold mu.
+func (s *sampler) prune(now time.Time) {
+	for k, e := range s.entries {
+		if now.Sub(e.start) >= s.cfg.Per && e.suppressed == 0 {
+			delete(s.entries, k)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// WithScope is NewContext for a block of work: the attributes are added to
+// records logged with the returned context (and contexts derived from it)
+// until end is called.
+//
+//	ctx, end := logs.WithScope(ctx, "batch", id)
+//	defer end()
+func WithScope(ctx context.Context, args ...any) (_ context.Context, end func()) {
+	ended := new(atomic.Bool)
+	return withFrame(ctx, args, ended), func() { ended.Store(true) }
+}
+
+// OpKey is the attribute that carries the operation name of StartOp.
+const OpKey = "op"
+
+type ctxOpKey struct{}
+
+// Op is an operation started with StartOp.
+type Op struct {
+	ctx    context.Context
+	name   string
+	parent *Op
+	start  time.Time
+	end    func()
+	ending atomic.Bool // End / EndErr called
+	done   atomic.Bool // ended and logged
+}
+
+// StartOp logs "Operation started" with op=name and attrs, and returns a
+// context scoped to the operation: records logged with it carry op=name
+// and attrs until the Op ends. Nested operations are named parent/child
+// and replace the parent's op attribute until they end.
+//
+//	ctx, op := logs.StartOp(ctx, "copy files", "src", src)
+//	return op.EndErr(copyAll(ctx, src, dst))
+//	// level=INFO msg="Operation finished" op="copy files" src=/data duration=1.2s
+func StartOp(ctx context.Context, name string, args ...any) (context.Context, *Op) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	op := &Op{name: name, start: time.Now()}
+	if parent := activeOp(ctx); parent != nil {
+		op.name = parent.name + "/" + name
+		op.parent = parent
+	}
+	ctx, op.end = WithScope(ctx, args...)
+	op.ctx = context.WithValue(ctx, ctxOpKey{}, op)
+
+	emit(op.ctx, slog.LevelInfo, "Operation started")
+	return op.ctx, op
+}
+
+// End logs "Operation finished" with the duration and ends the scope.
+// Only the first End / EndErr logs.
+func (o *Op) End() {
+	if o.ending.Swap(true) {
+		return
+	}
+	emit(o.ctx, slog.LevelInfo, "Operation finished", "duration", time.Since(o.start))
+	o.end()
+	o.done.Store(true)
+}
+
+// EndErr is End for err == nil; otherwise it logs "Operation failed" at
+// error level with the error. It returns err.
+func (o *Op) EndErr(err error) error {
+	if o.ending.Swap(true) {
+		return err
+	}
+	if err == nil {
+		emit(o.ctx, slog.LevelInfo, "Operation finished", "duration", time.Since(o.start))
+	} else {
+		emit(o.ctx, slog.LevelError, "Operation failed", "duration", time.Since(o.start), "err", err)
+	}
+	o.end()
+	o.done.Store(true)
+	return err
+}
+
+// activeOp returns the innermost operation of ctx that hasn't ended.
+func activeOp(ctx context.Context) *Op {
+	op, _ := ctx.Value(ctxOpKey{}).(*Op)
+	for op != nil && op.done.Load() {
+		op = op.parent
+	}
+	return op
+}
+
+// Context returns the operation's context.
+func (o *Op) Context() context.Context {
+	return o.ctx
+}
@@ -0,0 +1,132 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// defaultStackDepth is the frame limit when StackTrace.Depth is unset.
+const defaultStackDepth = 32
+
+// maxGoroutineDump bounds the all-goroutines dump.
+const maxGoroutineDump = 1 << 20
+
+// StackTrace attaches the caller's stack to important records as a
+// "stack" attribute (a list of "function file:line" frames).
+type StackTrace struct {
+	Enabled bool
+
+	// MinLevel is the lowest level that gets a stack (default: error).
+	MinLevel slog.Leveler
+
+	// Depth limits the number of frames (default 32).
+	Depth int
+
+	// AllGoroutines additionally dumps every goroutine's stack as a
+	// "goroutines" attribute (like a panic), for deadlock postmortems.
+	// Expensive: it stops the world.
+	AllGoroutines bool
+}
+
+type stackHandler struct {
+	h   slog.Handler
+	cfg StackTrace
+}
+
+func newStackHandler(h slog.Handler, cfg StackTrace) slog.Handler {
+	if !cfg.Enabled {
+		return h
+	}
+	if cfg.MinLevel == nil {
+		cfg.MinLevel = slog.LevelError
+	}
+	if cfg.Depth <= 0 {
+		cfg.Depth = defaultStackDepth
+	}
+	return &stackHandler{h: h, cfg: cfg}
+}
+
+func (s *stackHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.h.Enabled(ctx, level)
+}
+
+func (s *stackHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= s.cfg.MinLevel.Level() && !hasAttr(r, "stack") {
+		r = r.Clone()
+		r.AddAttrs(slog.Any("stack", callerStack(r.PC, s.cfg.Depth)))
+		if s.cfg.AllGoroutines {
+			buf := make([]byte, 64<<10)
+			for {
+				n := runtime.Stack(buf, true)
+				if n < len(buf) || len(buf) >= maxGoroutineDump {
+					buf = buf[:n]
+					break
+				}
+				buf = make([]byte, 2*len(buf))
+			}
+			r.AddAttrs(slog.String("goroutines", string(buf)))
+		}
+	}
+	return s.h.Handle(ctx, r)
+}
+
+func (s *stackHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &stackHandler{h: s.h.WithAttrs(attrs), cfg: s.cfg}
+}
+
+func (s *stackHandler) WithGroup(name string) slog.Handler {
+	return &stackHandler{h: s.h.WithGroup(name), cfg: s.cfg}
+}
+
+// hasAttr reports whether r has a top-level attribute key (e.g. a stack
+// from RecoverAndLog).
+func hasAttr(r slog.Record, key string) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == key
+		return !found
+	})
+	return found
+}
+
+// callerStack returns up to depth frames starting at the logging call (pc,
+// the record's PC). Without a PC, the frames of slog and this package are
+// skipped instead.
+func callerStack(pc uintptr, depth int) []string {
+	pcs := make([]uintptr, depth+64)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+
+	start := -1
+	for i, p := range pcs {
+		if p == pc && pc != 0 {
+			start = i
+			break
+		}
+	}
+
+	frames := runtime.CallersFrames(pcs[max(start, 0):])
+	out := make([]string, 0, depth)
+	for len(out) < depth {
+		f, more := frames.Next()
+		if start < 0 && isLoggingFrame(f.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+		start = 0 // past the logging machinery
+		out = append(out, f.Function+" "+f.File+":"+strconv.Itoa(f.Line))
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+func isLoggingFrame(fn string) bool {
+	return strings.HasPrefix(fn, "log/slog.") ||
+		strings.HasPrefix(fn, "github.com/toobprojects/go-commons/logs.")
+}
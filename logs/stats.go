@@ -0,0 +1,137 @@
+package logs
+
+import (
+	"bufio"
+	"cmp"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// counters counts logged records by group and level.
+var counters struct {
+	m     sync.Map // statKey → *atomic.Uint64
+	since atomic.Pointer[time.Time]
+}
+
+type statKey struct {
+	group string
+	level slog.Level
+}
+
+func init() {
+	now := time.Now()
+	counters.since.Store(&now)
+}
+
+// countRecord is called for every record that passes the level checks,
+// before sampling and deduplication, so suppressed repeats still count.
+func countRecord(group string, level slog.Level) {
+	k := statKey{group, level}
+	c, ok := counters.m.Load(k)
+	if !ok {
+		c, _ = counters.m.LoadOrStore(k, new(atomic.Uint64))
+	}
+	c.(*atomic.Uint64).Add(1)
+}
+
+// Counts is a snapshot of the record counters, see Stats.
+type Counts struct {
+	Levels map[slog.Level]uint64            // records per level
+	Groups map[string]map[slog.Level]uint64 // records per WithGroup path ("" = no group)
+	Since  time.Time                        // when counting started (or ResetStats)
+}
+
+// Total returns the number of records across all levels.
+func (c Counts) Total() uint64 {
+	var n uint64
+	for _, v := range c.Levels {
+		n += v
+	}
+	return n
+}
+
+// Stats returns how many records were logged per level and per group since
+// the program started or ResetStats. Records count once they pass the
+// level checks, including ones later dropped by Sampling or Dedupe, so
+// the error rate reflects what happened rather than what was written:
+//
+//	if logs.Stats().Levels[slog.LevelError] > 0 { ... }
+func Stats() Counts {
+	c := Counts{
+		Levels: map[slog.Level]uint64{},
+		Groups: map[string]map[slog.Level]uint64{},
+		Since:  *counters.since.Load(),
+	}
+	counters.m.Range(func(k, v any) bool {
+		key, n := k.(statKey), v.(*atomic.Uint64).Load()
+		c.Levels[key.level] += n
+		if c.Groups[key.group] == nil {
+			c.Groups[key.group] = map[slog.Level]uint64{}
+		}
+		c.Groups[key.group][key.level] += n
+		return true
+	})
+	return c
+}
+
+// ResetStats sets all counters back to zero.
+func ResetStats() {
+	counters.m.Clear()
+	now := time.Now()
+	counters.since.Store(&now)
+}
+
+// WritePrometheus writes the counters in the Prometheus text exposition
+// format, as the counter log_records_total with level and group labels:
+//
+//	log_records_total{level="error",group="cli"} 3
+func WritePrometheus(w io.Writer) error {
+	type row struct {
+		statKey
+		n uint64
+	}
+	var rows []row
+	counters.m.Range(func(k, v any) bool {
+		rows = append(rows, row{k.(statKey), v.(*atomic.Uint64).Load()})
+		return true
+	})
+	slices.SortFunc(rows, func(a, b row) int {
+		return cmp.Or(cmp.Compare(a.group, b.group), cmp.Compare(a.level, b.level))
+	})
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# HELP log_records_total Log records emitted, by level and group.\n")
+	bw.WriteString("# TYPE log_records_total counter\n")
+	for _, r := range rows {
+		bw.WriteString(`log_records_total{level="`)
+		bw.WriteString(promLabel(strings.ToLower(r.level.String())))
+		bw.WriteString(`",group="`)
+		bw.WriteString(promLabel(r.group))
+		bw.WriteString(`"} `)
+		bw.WriteString(strconv.FormatUint(r.n, 10))
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// MetricsHandler serves WritePrometheus for a Prometheus scrape target:
+//
+//	http.Handle("/metrics/logs", logs.MetricsHandler())
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WritePrometheus(w)
+	})
+}
+
+// promLabel escapes a label value.
+func promLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
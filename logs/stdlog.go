@@ -0,0 +1,154 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type stdLogOptions struct {
+	level      slog.Level // for lines without a level prefix
+	setDefault bool       // also slog.SetDefault
+}
+
+// StdLogOption configures RedirectStdLog.
+type StdLogOption func(*stdLogOptions)
+
+// WithStdLogLevel sets the level of lines without a recognized level
+// prefix (default info).
+func WithStdLogLevel(level slog.Level) StdLogOption {
+	return func(o *stdLogOptions) { o.level = level }
+}
+
+// WithSlogDefault also makes slog.Default() log through this package, so
+// libraries calling slog.Info etc. get the same outputs and levels.
+func WithSlogDefault() StdLogOption {
+	return func(o *stdLogOptions) { o.setDefault = true }
+}
+
+// RedirectStdLog routes the standard library's global logger (log.Printf
+// and friends, used by many dependencies) into this package. The level is
+// inferred from a prefix such as "[ERROR]", "warning:" or "DEBUG ", which
+// is removed from the message:
+//
+//	log.Printf("[WARN] retrying %s", url) // level=WARN msg="retrying https://…"
+//
+// Trace and debug map to debug; info and notice to info; warn and warning
+// to warn; error, err, fatal, panic and critical to error. With AddSource,
+// the source is the caller of log.Printf. Records follow Init.
+//
+// It returns a function that restores the previous log (and slog)
+// configuration.
+func RedirectStdLog(opts ...StdLogOption) (restore func()) {
+	cfg := stdLogOptions{level: slog.LevelInfo}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	prevOut, prevFlags, prevPrefix := log.Writer(), log.Flags(), log.Prefix()
+	prevDefault := slog.Default()
+	if cfg.setDefault {
+		// slog.SetDefault redirects log to the new handler itself, so it
+		// must come before log.SetOutput.
+		slog.SetDefault(slog.New(&namedHandler{cache: &atomic.Pointer[namedCache]{}}))
+	}
+	log.SetOutput(&stdLogWriter{level: cfg.level})
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	return func() {
+		if cfg.setDefault {
+			slog.SetDefault(prevDefault)
+		}
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+		log.SetPrefix(prevPrefix)
+	}
+}
+
+// stdLogWriter receives one Write per log call.
+type stdLogWriter struct {
+	level slog.Level
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	level, msg := inferLevel(w.level, string(bytes.TrimRight(p, "\r\n")))
+
+	ctx := context.Background()
+	l := get()
+	if !l.Enabled(ctx, level) {
+		return len(p), nil
+	}
+	r := slog.NewRecord(time.Now(), level, msg, stdLogCaller())
+	_ = l.Handler().Handle(ctx, r)
+	return len(p), nil
+}
+
+// stdLogCaller returns the PC of the first caller outside the log package.
+func stdLogCaller() uintptr {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:]) // skip [Callers, stdLogCaller, Write]
+	for _, pc := range pcs[:n] {
+		f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		if !strings.HasPrefix(f.Function, "log.") {
+			return pc
+		}
+	}
+	return 0
+}
+
+var levelPrefixes = map[string]slog.Level{
+	"trace":    slog.LevelDebug,
+	"debug":    slog.LevelDebug,
+	"info":     slog.LevelInfo,
+	"notice":   slog.LevelInfo,
+	"warn":     slog.LevelWarn,
+	"warning":  slog.LevelWarn,
+	"error":    slog.LevelError,
+	"err":      slog.LevelError,
+	"fatal":    slog.LevelError,
+	"panic":    slog.LevelError,
+	"critical": slog.LevelError,
+	"crit":     slog.LevelError,
+}
+
+// inferLevel recognizes "[LEVEL] msg", "level: msg" (any case) and
+// "LEVEL msg" (upper case only, so "error connecting" stays as it is) and
+// returns the level and the message without the prefix.
+func inferLevel(def slog.Level, msg string) (slog.Level, string) {
+	s := msg
+	bracket := strings.HasPrefix(s, "[")
+	if bracket {
+		s = s[1:]
+	}
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end <= 0 {
+		return def, msg
+	}
+	word, rest := s[:end], s[end:]
+	level, ok := levelPrefixes[strings.ToLower(word)]
+	if !ok {
+		return def, msg
+	}
+
+	switch {
+	case bracket:
+		if !strings.HasPrefix(rest, "]") {
+			return def, msg
+		}
+		rest = strings.TrimPrefix(rest[1:], ":")
+	case strings.HasPrefix(rest, ":"):
+		rest = rest[1:]
+	case strings.HasPrefix(rest, " ") && word == strings.ToUpper(word):
+	default:
+		return def, msg
+	}
+	return level, strings.TrimLeft(rest, " \t")
+}
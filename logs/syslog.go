@@ -0,0 +1,96 @@
+package logs
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSystemLogUnsupported is reported when the selected system log isn't
+// available on this platform.
+var ErrSystemLogUnsupported = errors.New("system log not supported on this platform")
+
+// SystemLog selects a local system logging daemon for Config.SystemLog.
+type SystemLog int
+
+const (
+	NoSystemLog SystemLog = iota
+	Syslog                // local syslog daemon (Unix)
+	Journald              // systemd-journald native protocol (Linux)
+)
+
+func (s SystemLog) String() string {
+	switch s {
+	case Syslog:
+		return "syslog"
+	case Journald:
+		return "journald"
+	default:
+		return "none"
+	}
+}
+
+// systemSink delivers one record to the system log.
+type systemSink interface {
+	send(level slog.Level, msg string, fields []field) error
+}
+
+// newSystemHandler connects to the selected system log. tag identifies the
+// program (SYSLOG_IDENTIFIER); it defaults to the executable name.
+func newSystemHandler(kind SystemLog, tag string, opts *slog.HandlerOptions) (slog.Handler, error) {
+	if tag == "" {
+		tag = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	}
+
+	var (
+		sink systemSink
+		err  error
+	)
+	switch kind {
+	case Syslog:
+		sink, err = openSyslog(tag)
+	case Journald:
+		sink, err = openJournal(tag)
+	default:
+		return nil, ErrSystemLogUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newFieldHandler(opts, func(r slog.Record, msg slog.Attr, fields []field) error {
+		return sink.send(r.Level, msg.Value.String(), fields)
+	}), nil
+}
+
+// syslogPriority maps a slog level to a syslog severity (0 = emerg ...
+// 7 = debug).
+func syslogPriority(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError+4:
+		return 2 // crit
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo+2:
+		return 5 // notice
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// syslogText renders msg followed by the fields in logfmt, for plain
+// syslog.
+func syslogText(msg string, fields []field) string {
+	b := []byte(msg)
+	for _, f := range fields {
+		b = append(b, ' ')
+		b = appendLogfmt(b, f.key, f.value.String())
+	}
+	return string(b)
+}
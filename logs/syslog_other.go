@@ -0,0 +1,7 @@
+//go:build !unix
+
+package logs
+
+func openSyslog(string) (systemSink, error) {
+	return nil, ErrSystemLogUnsupported
+}
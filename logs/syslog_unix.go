@@ -0,0 +1,38 @@
+//go:build unix
+
+package logs
+
+import (
+	"log/slog"
+	"log/syslog"
+)
+
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func openSyslog(tag string) (systemSink, error) {
+	w, err := syslog.New(syslog.LOG_USER|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) send(level slog.Level, msg string, fields []field) error {
+	text := syslogText(msg, fields)
+	switch syslogPriority(level) {
+	case 2:
+		return s.w.Crit(text)
+	case 3:
+		return s.w.Err(text)
+	case 4:
+		return s.w.Warning(text)
+	case 5:
+		return s.w.Notice(text)
+	case 6:
+		return s.w.Info(text)
+	default:
+		return s.w.Debug(text)
+	}
+}
@@ -0,0 +1,45 @@
+package logs
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Special Config.TimeFormat values for numeric timestamps.
+const (
+	TimeUnix      = "unix"   // seconds since the epoch
+	TimeUnixMilli = "unixms" // milliseconds since the epoch
+	TimeUnixNano  = "unixns" // nanoseconds since the epoch
+)
+
+// timeAttr returns a ReplaceAttr function that formats (or, with omit,
+// drops) the record timestamp, or nil when slog's default is wanted.
+func timeAttr(layout string, loc *time.Location, omit bool) func([]string, slog.Attr) slog.Attr {
+	if !omit && layout == "" && loc == nil {
+		return nil
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.TimeKey || a.Value.Kind() != slog.KindTime {
+			return a
+		}
+		if omit {
+			return slog.Attr{}
+		}
+		t := a.Value.Time()
+		if loc != nil {
+			t = t.In(loc)
+		}
+		switch layout {
+		case "":
+			return slog.Time(a.Key, t)
+		case TimeUnix:
+			return slog.Int64(a.Key, t.Unix())
+		case TimeUnixMilli:
+			return slog.Int64(a.Key, t.UnixMilli())
+		case TimeUnixNano:
+			return slog.Int64(a.Key, t.UnixNano())
+		default:
+			return slog.String(a.Key, t.Format(layout))
+		}
+	}
+}
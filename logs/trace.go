@@ -0,0 +1,45 @@
+package logs
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// TraceExtractor returns the trace and span IDs of the span active in ctx,
+// with ok = false when there is none.
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+var traceExtractor atomic.Pointer[TraceExtractor]
+
+// SetTraceExtractor makes the *Ctx helpers (and FromContext) attach
+// trace_id and span_id attributes whenever the context carries an active
+// span. This package has no tracing dependency; wire OpenTelemetry (or any
+// other tracer) with a few lines:
+//
+//	logs.SetTraceExtractor(func(ctx context.Context) (string, string, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.SpanID().String(), sc.IsValid()
+//	})
+//
+// Pass nil to turn it off.
+func SetTraceExtractor(fn TraceExtractor) {
+	if fn == nil {
+		traceExtractor.Store(nil)
+		return
+	}
+	traceExtractor.Store(&fn)
+}
+
+// traceAttrs returns the trace_id / span_id attributes for ctx, if any.
+func traceAttrs(ctx context.Context) []slog.Attr {
+	fn := traceExtractor.Load()
+	if fn == nil || ctx == nil {
+		return nil
+	}
+	traceID, spanID, ok := (*fn)(ctx)
+	if !ok {
+		return nil
+	}
+	return []slog.Attr{slog.String("trace_id", traceID), slog.String("span_id", spanID)}
+}
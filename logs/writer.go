@@ -0,0 +1,84 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// maxLineLen bounds a buffered line; longer lines are logged in pieces.
+const maxLineLen = 64 << 10
+
+// Writer returns an io.Writer that logs every line written to it as a
+// record at level with attrs, e.g. to capture a subprocess' stderr or the
+// output of a library that only accepts an io.Writer:
+//
+//	opts := cli.Options{Stderr: logs.Writer(slog.LevelWarn, "cmd", "git")}
+//	lib.SetOutput(logs.Writer(slog.LevelInfo, "lib", "foo"))
+//	log.New(logs.Writer(slog.LevelInfo), "", 0) // for APIs taking *log.Logger
+//
+// Trailing "\r" and blank lines are dropped. Writes are safe for
+// concurrent use; a line is logged once its newline arrives, so the
+// returned writer also implements io.Closer to log an unterminated last
+// line. Records go through the current global logger, following Init.
+func Writer(level slog.Level, attrs ...any) io.Writer {
+	return &lineWriter{level: level, attrs: attrs}
+}
+
+type lineWriter struct {
+	level slog.Level
+	attrs []any
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	rest := w.buf
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(rest[:i])
+		rest = rest[i+1:]
+	}
+	for len(rest) >= maxLineLen {
+		w.log(rest[:maxLineLen])
+		rest = rest[maxLineLen:]
+	}
+	w.buf = append(w.buf[:0], rest...)
+	return len(p), nil
+}
+
+// Close logs a pending unterminated line.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.log(w.buf)
+	w.buf = w.buf[:0]
+	return nil
+}
+
+func (w *lineWriter) log(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	ctx := context.Background()
+	l := get()
+	if !l.Enabled(ctx, w.level) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), w.level, string(line), 0)
+	r.Add(w.attrs...)
+	_ = l.Handler().Handle(ctx, r)
+}
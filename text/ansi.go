@@ -0,0 +1,78 @@
+package text
+
+import "strings"
+
+// StripANSI removes ANSI escape sequences from s: colors and other SGR
+// codes, cursor movement (CSI sequences), hyperlinks and window titles
+// (OSC sequences) and two-character escapes. Use it to clean captured
+// colored command output before logging it.
+//
+//	text.StripANSI("\033[1;31merror\033[0m: disk full") // "error: disk full"
+func StripANSI(s string) string {
+	if !strings.ContainsAny(s, "\033\u009b") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if n := ansiLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// VisibleWidth is Width ignoring ANSI escape sequences: the number of
+// terminal columns s takes up when printed. Use it to lay out colored text.
+func VisibleWidth(s string) int {
+	return Width(StripANSI(s))
+}
+
+// ansiLen returns the length of the escape sequence at the start of s, or
+// 0 if there is none. An unterminated sequence runs to the end of s.
+func ansiLen(s string) int {
+	switch {
+	case strings.HasPrefix(s, "\u009b"): // 8-bit CSI
+		return 2 + csiLen(s[2:])
+	case len(s) < 2 || s[0] != '\033':
+		return 0
+	case s[1] == '[':
+		return 2 + csiLen(s[2:])
+	case s[1] == ']', s[1] == 'P', s[1] == '_', s[1] == '^': // OSC, DCS, APC, PM: up to BEL or ST
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	// Other escapes: ESC, intermediate bytes, one final byte, e.g. "\033(B".
+	i := 1
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+		i++
+	}
+	if i < len(s) && s[i] >= 0x30 && s[i] <= 0x7e {
+		return i + 1
+	}
+	return 0
+}
+
+// csiLen returns the length of a CSI sequence's parameters, intermediate
+// bytes and final byte.
+func csiLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+		if s[i] < 0x20 || s[i] > 0x3f {
+			return i // malformed: drop the introducer and what was read
+		}
+	}
+	return len(s)
+}
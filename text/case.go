@@ -0,0 +1,99 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ToCamel converts s to camelCase: "http_server_url" → "httpServerUrl".
+func ToCamel(s string) string {
+	words := Words(s)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = capitalize(w)
+		}
+	}
+	return strings.Join(words, EmptyText)
+}
+
+// ToPascal converts s to PascalCase: "http_server_url" → "HttpServerUrl".
+func ToPascal(s string) string {
+	words := Words(s)
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, EmptyText)
+}
+
+// ToSnake converts s to snake_case: "HTTPServer" → "http_server".
+func ToSnake(s string) string {
+	return joinWords(s, "_", strings.ToLower)
+}
+
+// ToKebab converts s to kebab-case: "HTTPServer" → "http-server".
+func ToKebab(s string) string {
+	return joinWords(s, "-", strings.ToLower)
+}
+
+// ToScreamingSnake converts s to SCREAMING_SNAKE_CASE: "httpServer" →
+// "HTTP_SERVER".
+func ToScreamingSnake(s string) string {
+	return joinWords(s, "_", strings.ToUpper)
+}
+
+// Words splits s into words for case conversion. Any character that is
+// not a letter or digit separates words, and so do case changes: a lower
+// case letter or digit followed by an upper case one ("userID" → user,
+// ID), and the end of an acronym before a word of two or more lower case
+// letters ("HTTPServer" → HTTP, Server; but "IPv4", "PDFs"). Digits stay
+// with the word before them ("utf8Decoder" → utf8, Decoder).
+func Words(s string) []string {
+	var (
+		words []string
+		start = -1 // start of the current word
+		prev  rune
+	)
+	for i, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		} else if unicode.IsUpper(r) {
+			rest := s[i+utf8.RuneLen(r):]
+			next, n := utf8.DecodeRuneInString(rest)
+			after, _ := utf8.DecodeRuneInString(rest[n:])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && unicode.IsLower(next) && unicode.IsLower(after)) {
+				words = append(words, s[start:i])
+				start = i
+			}
+		}
+		prev = r
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+func joinWords(s, sep string, convert func(string) string) string {
+	words := Words(s)
+	for i, w := range words {
+		words[i] = convert(w)
+	}
+	return strings.Join(words, sep)
+}
+
+// capitalize upper-cases the first letter of w and lower-cases the rest.
+func capitalize(w string) string {
+	r, n := utf8.DecodeRuneInString(w)
+	return string(unicode.ToUpper(r)) + strings.ToLower(w[n:])
+}
@@ -0,0 +1,93 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnclosedQuote is returned by SplitCommandLine for a quote that is
+// never closed.
+var ErrUnclosedQuote = errors.New("unclosed quote")
+
+// SplitCommandLine splits s into arguments the way a POSIX shell does,
+// without any expansion:
+//
+//   - unquoted white space separates arguments
+//   - 'single quotes' keep everything up to the next ' literally
+//   - "double quotes" keep everything except that \ escapes $ ` " \ and a
+//     newline
+//   - outside quotes, \ escapes the next character, and \ before a newline
+//     joins lines
+//   - an empty pair of quotes gives an empty argument
+//
+// Variables ($HOME), globs and other shell syntax are passed through as
+// text. The result can be passed to cli.Run.
+//
+//	args, err := text.SplitCommandLine(`git commit -m "fix: it's done"`)
+//	// ["git", "commit", "-m", "fix: it's done"]
+func SplitCommandLine(s string) ([]string, error) {
+	var (
+		args  []string
+		cur   strings.Builder
+		inArg bool // cur holds an argument, possibly empty ("")
+		quote byte // the open quote, or 0
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+
+		case quote == '"':
+			switch {
+			case c == '"':
+				quote = 0
+			case c == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0:
+				i++
+				if s[i] != '\n' {
+					cur.WriteByte(s[i])
+				}
+			default:
+				cur.WriteByte(c)
+			}
+
+		case c == '\\':
+			if i+1 == len(s) {
+				cur.WriteByte(c) // nothing to escape: keep it
+				inArg = true
+				break
+			}
+			i++
+			if s[i] != '\n' {
+				cur.WriteByte(s[i])
+				inArg = true
+			}
+
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+
+		default:
+			cur.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("split command line: %w (%c)", ErrUnclosedQuote, quote)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,287 @@
+package text
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EditOp is the kind of an Edit.
+type EditOp int
+
+const (
+	EditEqual  EditOp = iota // in both a and b
+	EditDelete               // only in a
+	EditInsert               // only in b
+)
+
+// Edit is one step of a diff. Text is a line, including its "\n" (the last
+// line may lack one), for Diff, or a word or run of white space for
+// DiffWords. Joining the Text of the EditEqual and EditDelete steps gives
+// back a; EditEqual and EditInsert give b.
+type Edit struct {
+	Op   EditOp
+	Text string
+}
+
+// Diff returns a shortest line-by-line diff turning a into b. Within each
+// changed block the deleted lines come before the inserted ones.
+func Diff(a, b string) []Edit {
+	return diffTokens(splitLines(a), splitLines(b))
+}
+
+// DiffWords is Diff by words: a and b are split into words and the white
+// space between them.
+func DiffWords(a, b string) []Edit {
+	return diffTokens(splitWords(a), splitWords(b))
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == EmptyText {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func splitWords(s string) []string {
+	var words []string
+	for len(s) > 0 {
+		r, _ := utf8.DecodeRuneInString(s)
+		space := unicode.IsSpace(r)
+		i := strings.IndexFunc(s, func(r rune) bool { return unicode.IsSpace(r) != space })
+		if i < 0 {
+			i = len(s)
+		}
+		words = append(words, s[:i])
+		s = s[i:]
+	}
+	return words
+}
+
+// diffTokens diffs two token lists with Myers' O(ND) algorithm, after
+// taking off the common prefix and suffix.
+func diffTokens(a, b []string) []Edit {
+	var prefix, suffix []Edit
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		prefix = append(prefix, Edit{EditEqual, a[0]})
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append(suffix, Edit{EditEqual, a[len(a)-1]})
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	slices.Reverse(suffix)
+
+	edits := append(prefix, myers(a, b)...)
+	return groupChanges(append(edits, suffix...))
+}
+
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+	// v[off+k] is the furthest x reached on diagonal k = x-y. trace[d]
+	// holds v[-d-1 .. d+1] as it was before step d, for the way back.
+	off := n + m + 1
+	v := make([]int, 2*off+1)
+	var trace [][]int
+	for d := 0; ; d++ {
+		trace = append(trace, slices.Clone(v[off-d-1:off+d+2]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1] // down: insert b[y]
+			} else {
+				x = v[off+k-1] + 1 // right: delete a[x]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return myersPath(a, b, trace)
+			}
+		}
+	}
+}
+
+func myersPath(a, b []string, trace [][]int) []Edit {
+	var edits []Edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := func(k int) int { return trace[d][k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{EditEqual, a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, Edit{EditInsert, b[y-1]})
+			} else {
+				edits = append(edits, Edit{EditDelete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(edits)
+	return edits
+}
+
+// groupChanges moves the deletions of each run of changes before its
+// insertions.
+func groupChanges(edits []Edit) []Edit {
+	for i := 0; i < len(edits); {
+		if edits[i].Op == EditEqual {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != EditEqual {
+			j++
+		}
+		slices.SortStableFunc(edits[i:j], func(x, y Edit) int { return int(x.Op - y.Op) })
+		i = j
+	}
+	return edits
+}
+
+type diffOptions struct {
+	context int  // unchanged lines around changes
+	color   bool // ANSI colors
+}
+
+// DiffOption configures UnifiedDiff.
+type DiffOption func(*diffOptions)
+
+// DiffContext sets the number of unchanged lines shown around each change
+// (default 3).
+func DiffContext(lines int) DiffOption {
+	return func(o *diffOptions) { o.context = max(lines, 0) }
+}
+
+// DiffColor colors the output for a terminal: deletions red, insertions
+// green, hunk headers cyan.
+func DiffColor() DiffOption {
+	return func(o *diffOptions) { o.color = true }
+}
+
+const (
+	diffReset = "\033[0m"
+	diffBold  = "\033[1m"
+	diffRed   = "\033[31m"
+	diffGreen = "\033[32m"
+	diffCyan  = "\033[36m"
+)
+
+// UnifiedDiff renders the line edits from Diff in unified format, as
+// "diff -u" does, with fromName and toName in the header. It returns ""
+// when there are no changes.
+//
+//	fmt.Print(text.UnifiedDiff("existing.yaml", "generated.yaml", text.Diff(old, gen)))
+func UnifiedDiff(fromName, toName string, edits []Edit, opts ...DiffOption) string {
+	o := diffOptions{context: 3}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	paint := func(color, s string) string {
+		if !o.color {
+			return s
+		}
+		return color + s + diffReset
+	}
+
+	var b strings.Builder
+	aLine, bLine := 1, 1 // line numbers at edits[i]
+	for i := 0; i < len(edits); {
+		// Find the next change; the hunk starts context lines before it
+		// and runs until a gap of more than 2*context unchanged lines.
+		first := i
+		for first < len(edits) && edits[first].Op == EditEqual {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		start := max(first-o.context, i)
+		end := first
+		for {
+			for end < len(edits) && edits[end].Op != EditEqual {
+				end++
+			}
+			next := end
+			for next < len(edits) && edits[next].Op == EditEqual {
+				next++
+			}
+			if next == len(edits) || next-end > 2*o.context {
+				break
+			}
+			end = next
+		}
+		end = min(end+o.context, len(edits))
+
+		aLine += start - i // the lines skipped are all equal
+		bLine += start - i
+		var aCount, bCount int
+		for _, e := range edits[start:end] {
+			if e.Op != EditInsert {
+				aCount++
+			}
+			if e.Op != EditDelete {
+				bCount++
+			}
+		}
+
+		if b.Len() == 0 {
+			b.WriteString(paint(diffBold, "--- "+fromName) + "\n")
+			b.WriteString(paint(diffBold, "+++ "+toName) + "\n")
+		}
+		b.WriteString(paint(diffCyan, fmt.Sprintf("@@ -%s +%s @@", hunkRange(aLine, aCount), hunkRange(bLine, bCount))) + "\n")
+		for _, e := range edits[start:end] {
+			prefix, color := " ", EmptyText
+			switch e.Op {
+			case EditDelete:
+				prefix, color = "-", diffRed
+				aLine++
+			case EditInsert:
+				prefix, color = "+", diffGreen
+				bLine++
+			default:
+				aLine, bLine = aLine+1, bLine+1
+			}
+			line := prefix + strings.TrimSuffix(e.Text, "\n")
+			if color != EmptyText {
+				line = paint(color, line)
+			}
+			b.WriteString(line + "\n")
+			if !strings.HasSuffix(e.Text, "\n") {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats a line range for a hunk header: "start,count", or
+// just "start" for one line; an empty range names the line before it.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
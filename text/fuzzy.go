@@ -0,0 +1,185 @@
+package text
+
+import (
+	"cmp"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Distance returns the Levenshtein distance between a and b: the number of
+// single-character insertions, deletions and substitutions that turn a
+// into b. Characters are runes, so "café" and "cafe" are 1 apart.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra // keep the rows short
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range ra {
+		cur[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// Similarity returns how alike a and b are, from 0 (nothing in common) to
+// 1 (equal), as 1 - Distance / the length of the longer string.
+func Similarity(a, b string) float64 {
+	n := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(Distance(a, b))/float64(n)
+}
+
+// Match is a candidate matched by FuzzyMatch.
+type Match struct {
+	Text      string // the candidate
+	Index     int    // its index in the candidates
+	Score     int    // higher is a better match
+	Positions []int  // byte offsets in Text of the matched characters, for highlighting
+}
+
+// Fuzzy match scoring: every matched character scores, more so at the
+// start of a word or right after the previous match, and skipped
+// characters between matches cost a little.
+const (
+	fuzzyMatch       = 16
+	fuzzyBoundary    = 8
+	fuzzyConsecutive = 8
+	fuzzyGap         = 1
+)
+
+// FuzzyMatch returns the candidates that contain the characters of needle
+// in order, though not necessarily together ("fb" matches "FooBar" and
+// "fizzbuzz"), ignoring case, best match first. Matches at word starts
+// ("fb" in "foo_bar" or "FooBar") and runs of consecutive characters rank
+// higher; equal scores rank shorter candidates first, then keep the
+// candidates' order. An empty needle matches everything in order.
+//
+//	for _, m := range text.FuzzyMatch("gco", files) {
+//		fmt.Println(m.Text, m.Score)
+//	}
+func FuzzyMatch(needle string, candidates []string) []Match {
+	pattern := []rune(needle)
+	for i, r := range pattern {
+		pattern[i] = unicode.ToLower(r)
+	}
+	var matches []Match
+	for i, c := range candidates {
+		if score, pos, ok := fuzzyScore(pattern, c); ok {
+			matches = append(matches, Match{Text: c, Index: i, Score: score, Positions: pos})
+		}
+	}
+	if len(pattern) == 0 {
+		return matches
+	}
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		if a.Score != b.Score {
+			return cmp.Compare(b.Score, a.Score)
+		}
+		return cmp.Compare(utf8.RuneCountInString(a.Text), utf8.RuneCountInString(b.Text))
+	})
+	return matches
+}
+
+// fuzzyScore finds the best-scoring way to match pattern (lower case) as a
+// subsequence of s.
+func fuzzyScore(pattern []rune, s string) (int, []int, bool) {
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+	var (
+		runes   []rune
+		offsets []int
+	)
+	for i, r := range s {
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+	}
+	n, m := len(pattern), len(runes)
+	if n > m {
+		return 0, nil, false
+	}
+
+	// score[i][j] is the best score for pattern[:i+1] with pattern[i]
+	// matched at runes[j] (noMatch if impossible); from[i][j] is where
+	// pattern[i-1] was matched on that path.
+	const noMatch = -1 << 30
+	score := make([][]int, n)
+	from := make([][]int, n)
+	for i := range pattern {
+		score[i] = make([]int, m)
+		from[i] = make([]int, m)
+		// best is max(score[i-1][k] + k*fuzzyGap) over k < j-1, for a
+		// match after a gap; bestAt is its k.
+		best, bestAt := noMatch, -1
+		for j := range runes {
+			score[i][j] = noMatch
+			if i > 0 && j >= 2 && score[i-1][j-2] != noMatch {
+				if v := score[i-1][j-2] + (j-2)*fuzzyGap; v > best {
+					best, bestAt = v, j-2
+				}
+			}
+			if unicode.ToLower(runes[j]) != pattern[i] {
+				continue
+			}
+			char := fuzzyMatch
+			if isWordStart(runes, j) {
+				char += fuzzyBoundary
+			}
+			if i == 0 {
+				score[i][j] = char
+				continue
+			}
+			if j > 0 && score[i-1][j-1] != noMatch {
+				score[i][j], from[i][j] = score[i-1][j-1]+fuzzyConsecutive+char, j-1
+			}
+			if best != noMatch {
+				if v := best - (j-1)*fuzzyGap + char; v > score[i][j] {
+					score[i][j], from[i][j] = v, bestAt
+				}
+			}
+		}
+	}
+
+	end := -1
+	for j := range runes {
+		if score[n-1][j] != noMatch && (end < 0 || score[n-1][j] > score[n-1][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+	positions := make([]int, n)
+	for i, j := n-1, end; i >= 0; i-- {
+		positions[i] = offsets[j]
+		j = from[i][j]
+	}
+	return score[n-1][end], positions, true
+}
+
+// isWordStart reports whether runes[j] starts a word: it is the first
+// character, follows a separator, or is an upper case letter after a lower
+// case one.
+func isWordStart(runes []rune, j int) bool {
+	if j == 0 {
+		return true
+	}
+	prev, r := runes[j-1], runes[j]
+	return (!unicode.IsLetter(prev) && !unicode.IsDigit(prev)) ||
+		(unicode.IsLower(prev) && unicode.IsUpper(r))
+}
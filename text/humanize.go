@@ -0,0 +1,145 @@
+package text
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// HumanBytes formats a byte count with binary (1024-based) units and at
+// most one decimal: 512 → "512 B", 1536 → "1.5 KiB", 1503238553 →
+// "1.4 GiB".
+func HumanBytes(n int64) string {
+	sign := EmptyText
+	u := uint64(n)
+	if n < 0 {
+		sign, u = "-", uint64(-(n+1))+1 // also right for math.MinInt64
+	}
+	if u < 1024 {
+		return sign + strconv.FormatUint(u, 10) + " B"
+	}
+	v, unit := float64(u), 0
+	for unit < len(byteUnits)-1 && math.Round(v*10)/10 >= 1024 {
+		v /= 1024
+		unit++
+	}
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	return sign + strings.TrimSuffix(s, ".0") + " " + byteUnits[unit]
+}
+
+// ParseBytes parses a size such as "512", "64k", "512MB" or "1.5 GiB" into
+// bytes. Units are case-insensitive: KiB, MiB, … and the single letters k,
+// m, g, t, p, e are binary (1024-based), as in JVM and Docker flags; KB,
+// MB, … are decimal (1000-based); B or no unit is bytes.
+func ParseBytes(s string) (int64, error) {
+	str := strings.TrimSpace(s)
+	i := strings.IndexFunc(str, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(str)
+	}
+	num, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	mult := float64(1)
+	if unit != EmptyText && unit != "b" {
+		exp := strings.IndexByte("kmgtpe", unit[0]) + 1
+		if exp == 0 {
+			return 0, fmt.Errorf("invalid byte size %q: unknown unit", s)
+		}
+		switch unit[1:] {
+		case EmptyText, "i", "ib":
+			mult = math.Pow(1024, float64(exp))
+		case "b":
+			mult = math.Pow(1000, float64(exp))
+		default:
+			return 0, fmt.Errorf("invalid byte size %q: unknown unit", s)
+		}
+	}
+	if v*mult >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: too large", s)
+	}
+	return int64(v * mult), nil
+}
+
+// HumanDuration formats d with its two largest units, dropping the rest:
+// "2h 3m", "1d 4h", "45s", "1m 30s". Durations under a second use one
+// unit: "350ms", "12µs".
+func HumanDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + HumanDuration(-max(d, -math.MaxInt64)) // -MinInt64 overflows
+	}
+	switch {
+	case d == 0:
+		return "0s"
+	case d < time.Microsecond:
+		return strconv.FormatInt(int64(d), 10) + "ns"
+	case d < time.Millisecond:
+		return strconv.FormatInt(int64(d/time.Microsecond), 10) + "µs"
+	case d < time.Second:
+		return strconv.FormatInt(int64(d/time.Millisecond), 10) + "ms"
+	}
+
+	units := []struct {
+		size time.Duration
+		name string
+	}{{24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}}
+	for i, u := range units {
+		if d < u.size {
+			continue
+		}
+		s := strconv.FormatInt(int64(d/u.size), 10) + u.name
+		if i+1 < len(units) {
+			next := units[i+1]
+			if n := d % u.size / next.size; n > 0 {
+				s += " " + strconv.FormatInt(int64(n), 10) + next.name
+			}
+		}
+		return s
+	}
+	return EmptyText // not reached: d >= time.Second
+}
+
+// HumanTimeAgo describes t relative to now in words: "just now",
+// "1 minute ago", "3 days ago", "2 months ago", or "in 5 minutes" for a
+// time in the future. Months are 30 days and years 365.
+func HumanTimeAgo(t time.Time) string {
+	d := time.Since(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < 10*time.Second {
+		return "just now"
+	}
+
+	var n int64
+	var unit string
+	switch day := 24 * time.Hour; {
+	case d < time.Minute:
+		n, unit = int64(d/time.Second), "second"
+	case d < time.Hour:
+		n, unit = int64(d/time.Minute), "minute"
+	case d < day:
+		n, unit = int64(d/time.Hour), "hour"
+	case d < 30*day:
+		n, unit = int64(d/day), "day"
+	case d < 365*day:
+		n, unit = int64(d/(30*day)), "month"
+	default:
+		n, unit = int64(d/(365*day)), "year"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	if future {
+		return fmt.Sprintf("in %d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
@@ -0,0 +1,49 @@
+package text
+
+import "strings"
+
+// MaskRune replaces hidden characters in masked values.
+const MaskRune = '*'
+
+// MaskLeft hides all but the last visible characters of s:
+// MaskLeft("4111111111111111", 4) is "************1111". At most half of s
+// is left visible, so short values are never shown in full.
+func MaskLeft(s string, visible int) string {
+	rs := []rune(s)
+	keep := clampVisible(visible, len(rs)/2)
+	return strings.Repeat(string(MaskRune), len(rs)-keep) + string(rs[len(rs)-keep:])
+}
+
+// MaskRight hides all but the first visible characters of s:
+// MaskRight("hunter2", 2) is "hu*****". At most half of s is left visible.
+func MaskRight(s string, visible int) string {
+	rs := []rune(s)
+	keep := clampVisible(visible, len(rs)/2)
+	return string(rs[:keep]) + strings.Repeat(string(MaskRune), len(rs)-keep)
+}
+
+// MaskEmail hides the local part of an email address except its first
+// character (all of it if it is one character long), keeping the domain:
+// "jane.doe@example.com" is "j*******@example.com". A value without "@" is
+// masked as MaskRight(s, 1).
+func MaskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return MaskRight(email, 1)
+	}
+	return MaskRight(email[:at], 1) + email[at:]
+}
+
+// MaskToken shows visible characters at each end of a secret such as an
+// API key, enough to tell keys apart, and a fixed "****" in between so the
+// length isn't revealed: MaskToken("ghp_x8K2mQ9vL4nR7tY1", 4) is
+// "ghp_****7tY1". At most a quarter of the token is shown at each end.
+func MaskToken(token string, visible int) string {
+	rs := []rune(token)
+	keep := clampVisible(visible, len(rs)/4)
+	return string(rs[:keep]) + strings.Repeat(string(MaskRune), 4) + string(rs[len(rs)-keep:])
+}
+
+func clampVisible(visible, limit int) int {
+	return max(min(visible, limit), 0)
+}
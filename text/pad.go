@@ -0,0 +1,169 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PadLeft right-aligns s in width terminal columns by adding pad on the
+// left. s is returned unchanged if it is already that wide. ANSI escape
+// sequences in s take up no columns, so colored text aligns too.
+//
+//	text.PadLeft("42", 5, ' ') // "   42"
+func PadLeft(s string, width int, pad rune) string {
+	return padding(width-VisibleWidth(s), pad) + s
+}
+
+// PadRight left-aligns s in width terminal columns by adding pad on the
+// right.
+func PadRight(s string, width int, pad rune) string {
+	return s + padding(width-VisibleWidth(s), pad)
+}
+
+// Center centers s in width terminal columns, with any odd column of
+// padding on the right.
+//
+//	text.Center("日本", 8, '-') // "--日本--"
+func Center(s string, width int, pad rune) string {
+	n := width - VisibleWidth(s)
+	return padding(n/2, pad) + s + padding(n-n/2, pad)
+}
+
+// padding returns n columns of pad; if pad is a wide character and n is
+// odd, the last column is a space.
+func padding(n int, pad rune) string {
+	if n <= 0 {
+		return EmptyText
+	}
+	w := max(runeWidth(pad), 1)
+	return strings.Repeat(string(pad), n/w) + strings.Repeat(" ", n%w)
+}
+
+// Width returns the number of terminal columns s takes up: east-asian wide
+// and fullwidth characters (CJK, most emoji) take two, combining marks and
+// other zero-width characters none, and everything else one.
+func Width(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0): // control characters
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf),
+		r == 0x200b,                  // zero width space
+		r >= 0xfe00 && r <= 0xfe0f,   // variation selectors
+		r >= 0x1f3fb && r <= 0x1f3ff: // emoji skin tones, part of the emoji before them
+		return 0
+	case r >= 0x1160 && r <= 0x11ff: // Hangul medial vowels and final consonants
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			break
+		}
+		if r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// wideRanges are the east-asian wide and fullwidth blocks, sorted.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},   // Hangul Jamo initial consonants
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x23e9, 0x23ec},   // media controls
+	{0x23f0, 0x23f0},   // alarm clock
+	{0x23f3, 0x23f3},   // hourglass with sand
+	{0x25fd, 0x25fe},   // small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x267f, 0x267f},   // wheelchair
+	{0x2693, 0x2693},   // anchor
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // circles
+	{0x26bd, 0x26be},   // balls
+	{0x26c4, 0x26c5},   // snowman, sun behind cloud
+	{0x26ce, 0x26ce},   // ophiuchus
+	{0x26d4, 0x26d4},   // no entry
+	{0x26ea, 0x26ea},   // church
+	{0x26f2, 0x26f3},   // fountain, golf
+	{0x26f5, 0x26f5},   // sailboat
+	{0x26fa, 0x26fa},   // tent
+	{0x26fd, 0x26fd},   // fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270a, 0x270b},   // fists
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x274e, 0x274e},   // cross mark button
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2757, 0x2757},   // exclamation mark
+	{0x2795, 0x2797},   // plus, minus, divide
+	{0x27b0, 0x27b0},   // curly loop
+	{0x27bf, 0x27bf},   // double curly loop
+	{0x2b1b, 0x2b1c},   // large squares
+	{0x2b50, 0x2b50},   // star
+	{0x2b55, 0x2b55},   // circle
+	{0x2e80, 0x303e},   // CJK radicals, Kangxi, CJK symbols and punctuation
+	{0x3041, 0x33ff},   // Hiragana, Katakana, Bopomofo, Hangul compatibility, CJK compatibility
+	{0x3400, 0x4dbf},   // CJK extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul Jamo extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility forms, small form variants
+	{0xff00, 0xff60},   // fullwidth forms
+	{0xffe0, 0xffe6},   // fullwidth signs
+	{0x16fe0, 0x16fe4}, // ideographic symbols
+	{0x17000, 0x18cff}, // Tangut
+	{0x1b000, 0x1b2ff}, // Kana supplement and extended
+	{0x1f004, 0x1f004}, // mahjong red dragon
+	{0x1f0cf, 0x1f0cf}, // joker
+	{0x1f18e, 0x1f18e}, // AB button
+	{0x1f191, 0x1f19a}, // squared words
+	{0x1f200, 0x1f202}, // squared katakana
+	{0x1f210, 0x1f23b}, // squared ideographs
+	{0x1f240, 0x1f248}, // tortoise shell bracketed ideographs
+	{0x1f250, 0x1f251}, // circled ideographs
+	{0x1f260, 0x1f265}, // rounded symbols
+	{0x1f300, 0x1f320}, // weather, landscape
+	{0x1f32d, 0x1f335}, // food, plants
+	{0x1f337, 0x1f37c}, // plants, food, drink
+	{0x1f37e, 0x1f393}, // drink, celebration
+	{0x1f3a0, 0x1f3ca}, // activities
+	{0x1f3cf, 0x1f3d3}, // sports
+	{0x1f3e0, 0x1f3f0}, // buildings
+	{0x1f3f4, 0x1f3f4}, // black flag
+	{0x1f3f8, 0x1f43e}, // sports, animals
+	{0x1f440, 0x1f440}, // eyes
+	{0x1f442, 0x1f4fc}, // people, objects
+	{0x1f4ff, 0x1f53d}, // objects, symbols
+	{0x1f54b, 0x1f54e}, // religious buildings
+	{0x1f550, 0x1f567}, // clock faces
+	{0x1f57a, 0x1f57a}, // man dancing
+	{0x1f595, 0x1f596}, // hand gestures
+	{0x1f5a4, 0x1f5a4}, // black heart
+	{0x1f5fb, 0x1f64f}, // landmarks, faces, gestures
+	{0x1f680, 0x1f6c5}, // transport
+	{0x1f6cc, 0x1f6cc}, // sleeping accommodation
+	{0x1f6d0, 0x1f6d2}, // place of worship, shopping cart
+	{0x1f6d5, 0x1f6d7}, // hindu temple, hut, elevator
+	{0x1f6dc, 0x1f6df}, // wireless, tools
+	{0x1f6eb, 0x1f6ec}, // airplane departure, arrival
+	{0x1f6f4, 0x1f6fc}, // scooters, vehicles
+	{0x1f7e0, 0x1f7eb}, // colored circles and squares
+	{0x1f7f0, 0x1f7f0}, // heavy equals sign
+	{0x1f90c, 0x1f93a}, // gestures, people, activities
+	{0x1f93c, 0x1f945}, // sports
+	{0x1f947, 0x1f9ff}, // medals, animals, food, people, objects
+	{0x1fa70, 0x1faff}, // symbols and pictographs extended A
+	{0x20000, 0x2fffd}, // CJK extensions B–F
+	{0x30000, 0x3fffd}, // CJK extension G and up
+}
@@ -0,0 +1,59 @@
+package text
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mathrand "math/rand/v2"
+	"strings"
+)
+
+// Charset is the set of characters Random picks from.
+type Charset string
+
+// Predefined charsets.
+const (
+	CharsetAlnum   Charset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	CharsetHex     Charset = "0123456789abcdef"
+	CharsetURLSafe Charset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz-_"
+	// CharsetHuman leaves out look-alikes (0/O/o, 1/l/I, …) for codes that
+	// people read out or type in.
+	CharsetHuman Charset = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghjkmnpqrstuvwxyz"
+)
+
+// Random returns n characters picked uniformly from charset with
+// crypto/rand, so it is fit for tokens, passwords and other secrets.
+// Charset may hold any runes; it panics if charset is empty.
+//
+//	token := text.Random(32, text.CharsetURLSafe)
+func Random(n int, charset Charset) string {
+	return randomString(n, charset, func(max uint32) uint32 {
+		// Rejection sampling keeps every character equally likely.
+		limit := -max % max // 2^32 mod max: values below it are biased
+		var buf [4]byte
+		for {
+			_, _ = rand.Read(buf[:]) // never fails
+			if v := binary.LittleEndian.Uint32(buf[:]); v >= limit {
+				return v % max
+			}
+		}
+	})
+}
+
+// FastRandom is Random with math/rand: cheaper, but predictable, so only
+// for non-secrets such as temp names, suffixes and test data.
+func FastRandom(n int, charset Charset) string {
+	return randomString(n, charset, mathrand.Uint32N)
+}
+
+func randomString(n int, charset Charset, intn func(uint32) uint32) string {
+	chars := []rune(charset)
+	if len(chars) == 0 {
+		panic("text: empty charset")
+	}
+	var b strings.Builder
+	b.Grow(n)
+	for range n {
+		b.WriteRune(chars[intn(uint32(len(chars)))])
+	}
+	return b.String()
+}
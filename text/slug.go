@@ -0,0 +1,119 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+type slugOptions struct {
+	maxLen int    // 0 = no limit
+	allow  string // extra characters kept as they are
+}
+
+// SlugOption configures Slugify.
+type SlugOption func(*slugOptions)
+
+// SlugMaxLength limits the slug to n characters. It is cut at a "-" where
+// possible so words stay whole.
+func SlugMaxLength(n int) SlugOption {
+	return func(o *slugOptions) { o.maxLen = n }
+}
+
+// SlugAllow keeps the given characters in the slug instead of replacing
+// them with "-", e.g. SlugAllow("._") for file names.
+func SlugAllow(chars string) SlugOption {
+	return func(o *slugOptions) { o.allow = chars }
+}
+
+// Slugify turns s into a lowercase slug that is safe in URLs and file
+// names: accented Latin letters are transliterated ("Crème Brûlée" →
+// "creme-brulee"), every other run of characters outside a-z and 0-9
+// becomes a single "-", and leading and trailing "-" are trimmed. The
+// result may be empty, e.g. for text in a non-Latin script.
+//
+//	text.Slugify("  Hello, Wörld! ")                    // "hello-world"
+//	text.Slugify("Q3 Report.pdf", text.SlugAllow(".")) // "q3-report.pdf"
+func Slugify(s string, opts ...SlugOption) string {
+	var o slugOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var b strings.Builder
+	dash := false // a "-" is pending before the next kept character
+	keep := func(str string) {
+		if dash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		dash = false
+		b.WriteString(str)
+	}
+	for _, r := range s {
+		r = unicode.ToLower(r)
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			keep(string(r))
+		case strings.ContainsRune(o.allow, r):
+			keep(string(r))
+		case unicode.Is(unicode.Mn, r):
+			// combining accent: dropped, the base letter was kept
+		case r == '\'' || r == '’':
+			// apostrophes join words: "don't" → "dont"
+		default:
+			if t, ok := transliterations[r]; ok {
+				keep(t)
+			} else {
+				dash = true
+			}
+		}
+	}
+
+	slug := []rune(b.String())
+	if o.maxLen > 0 && len(slug) > o.maxLen {
+		cut := slug[:o.maxLen]
+		if slug[o.maxLen] != '-' {
+			if i := lastIndexRune(cut, '-'); i > 0 {
+				cut = cut[:i]
+			}
+		}
+		return strings.TrimRight(string(cut), "-")
+	}
+	return string(slug)
+}
+
+// transliterations maps lowercase Latin letters with diacritics, and
+// ligatures, to ASCII.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ș': "s",
+	'ß': "ss",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'ț': "t",
+	'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+func lastIndexRune(rs []rune, r rune) int {
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,193 @@
+package text
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Align is the alignment of a Table column.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Table lays out rows of text in aligned columns, for CLI output. Widths
+// are measured in terminal columns, so wide characters and colored cells
+// line up. Build one with NewTable and the Set / AddRow methods, which
+// return the table for chaining:
+//
+//	t := text.NewTable("NAME", "SIZE", "STATUS").SetAlign(1, text.AlignRight)
+//	for _, f := range files {
+//		t.AddRow(f.Name, f.Size, f.Status)
+//	}
+//	fmt.Print(t)
+//
+// Without borders, columns are separated by two spaces, as tabwriter
+// output usually is; SetBorder(true) draws box lines around every cell.
+type Table struct {
+	headers  []string
+	rows     [][]string
+	align    map[int]Align
+	maxWidth map[int]int // -1: every column
+	border   bool
+}
+
+// NewTable returns a table with the given column headers (none for a table
+// without a header row).
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers, align: map[int]Align{}, maxWidth: map[int]int{}}
+}
+
+// AddRow appends a row; cells are formatted with fmt.Sprint. Rows may have
+// fewer cells than there are columns.
+func (t *Table) AddRow(cells ...any) *Table {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = fmt.Sprint(c)
+	}
+	t.rows = append(t.rows, row)
+	return t
+}
+
+// SetAlign sets the alignment of column col (from 0), header included.
+// Columns are left-aligned by default.
+func (t *Table) SetAlign(col int, a Align) *Table {
+	t.align[col] = a
+	return t
+}
+
+// SetMaxWidth limits column col to width terminal columns; longer cells
+// are cut with "…". A negative col sets the limit for every column without
+// its own.
+func (t *Table) SetMaxWidth(col, width int) *Table {
+	t.maxWidth[max(col, -1)] = width
+	return t
+}
+
+// SetBorder turns box-drawing borders on or off.
+func (t *Table) SetBorder(on bool) *Table {
+	t.border = on
+	return t
+}
+
+// String renders the table, one line per row, each ending in "\n".
+func (t *Table) String() string {
+	rows := t.cells()
+	if len(rows) == 0 {
+		return EmptyText
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for c, cell := range row {
+			widths[c] = max(widths[c], VisibleWidth(cell))
+		}
+	}
+
+	var b strings.Builder
+	rule := func(left, mid, right string) {
+		if !t.border {
+			return
+		}
+		b.WriteString(left)
+		for c, w := range widths {
+			if c > 0 {
+				b.WriteString(mid)
+			}
+			b.WriteString(strings.Repeat("─", w+2))
+		}
+		b.WriteString(right + "\n")
+	}
+
+	rule("┌", "┬", "┐")
+	for r, row := range rows {
+		if r == 1 && len(t.headers) > 0 {
+			rule("├", "┼", "┤")
+		}
+		var line strings.Builder
+		for c, cell := range row {
+			switch {
+			case t.border:
+				line.WriteString("│ ")
+			case c > 0:
+				line.WriteString("  ")
+			}
+			switch t.align[c] {
+			case AlignRight:
+				line.WriteString(PadLeft(cell, widths[c], ' '))
+			case AlignCenter:
+				line.WriteString(Center(cell, widths[c], ' '))
+			default:
+				line.WriteString(PadRight(cell, widths[c], ' '))
+			}
+			if t.border {
+				line.WriteByte(' ')
+			}
+		}
+		if t.border {
+			b.WriteString(line.String() + "│\n")
+		} else {
+			b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+		}
+	}
+	rule("└", "┴", "┘")
+	return b.String()
+}
+
+// WriteTo renders the table to w.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, t.String())
+	return int64(n), err
+}
+
+// cells returns the header and rows, all as wide as the widest row, with
+// line breaks and tabs replaced by spaces and the width limits applied.
+func (t *Table) cells() [][]string {
+	rows := t.rows
+	if len(t.headers) > 0 {
+		rows = append([][]string{t.headers}, rows...)
+	}
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+
+	out := make([][]string, len(rows))
+	for r, row := range rows {
+		out[r] = make([]string, cols)
+		for c, cell := range row {
+			cell = cellSpaces.Replace(cell)
+			limit, ok := t.maxWidth[c]
+			if !ok {
+				limit = t.maxWidth[-1]
+			}
+			out[r][c] = truncateWidth(cell, limit)
+		}
+	}
+	return out
+}
+
+var cellSpaces = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+// truncateWidth cuts s to at most width terminal columns, ending in "…",
+// without splitting a character. Escape sequences are dropped from cut
+// cells. A width of 0 or less means no limit.
+func truncateWidth(s string, width int) string {
+	if width <= 0 || VisibleWidth(s) <= width {
+		return s
+	}
+	s = StripANSI(s)
+	cut, w := 0, 0
+	for cut < len(s) {
+		n := nextGrapheme(s[cut:])
+		cw := Width(s[cut : cut+n])
+		if w+cw > width-1 {
+			break
+		}
+		cut, w = cut+n, w+cw
+	}
+	return s[:cut] + "…"
+}
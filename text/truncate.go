@@ -0,0 +1,129 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type truncateOptions struct {
+	graphemes bool // count grapheme clusters instead of runes
+	words     bool // cut at a word boundary
+}
+
+// TruncateOption configures Truncate.
+type TruncateOption func(*truncateOptions)
+
+// ByGraphemes counts user-perceived characters instead of runes, so an
+// accented letter written with a combining mark, a flag or an emoji with a
+// skin tone or ZWJ sequence counts as one and is never split.
+func ByGraphemes() TruncateOption {
+	return func(o *truncateOptions) { o.graphemes = true }
+}
+
+// AtWordBoundary cuts at the last space that fits instead of mid-word. A
+// single word longer than the limit is still cut.
+func AtWordBoundary() TruncateOption {
+	return func(o *truncateOptions) { o.words = true }
+}
+
+// Truncate shortens s to at most max characters (runes, or grapheme
+// clusters with ByGraphemes), including the ellipsis that is appended when
+// s is cut. It never splits a multi-byte character. If the ellipsis alone
+// doesn't fit, s is cut to max without it.
+//
+//	text.Truncate("Hello, wörld", 8, "…") // "Hello, …"
+//	text.Truncate("the quick brown fox", 12, "...", text.AtWordBoundary())
+//	// "the quick..."
+func Truncate(s string, max int, ellipsis string, opts ...TruncateOption) string {
+	var o truncateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	next := nextRune
+	if o.graphemes {
+		next = nextGrapheme
+	}
+
+	if max <= 0 {
+		return EmptyText
+	}
+	if countUnits(s, next) <= max {
+		return s
+	}
+	keep := max - countUnits(ellipsis, next)
+	if keep < 0 {
+		keep, ellipsis = max, EmptyText
+	}
+
+	// cut is the byte offset after keep units.
+	cut := 0
+	for n := 0; n < keep && cut < len(s); n++ {
+		cut += next(s[cut:])
+	}
+	if o.words {
+		if i := strings.LastIndexFunc(s[:cut+next(s[cut:])], unicode.IsSpace); i > 0 && i <= cut {
+			if head := strings.TrimRightFunc(s[:i], unicode.IsSpace); head != EmptyText {
+				cut = len(head)
+			}
+		}
+	}
+	return s[:cut] + ellipsis
+}
+
+func countUnits(s string, next func(string) int) int {
+	n := 0
+	for i := 0; i < len(s); i += next(s[i:]) {
+		n++
+	}
+	return n
+}
+
+func nextRune(s string) int {
+	_, n := utf8.DecodeRuneInString(s)
+	return n
+}
+
+// nextGrapheme returns the byte length of the grapheme cluster at the start
+// of s. It follows the common cases of Unicode's segmentation rules (CR LF,
+// combining marks, variation selectors, emoji modifiers and ZWJ sequences,
+// regional indicator pairs) rather than the full tables.
+func nextGrapheme(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == '\r' && strings.HasPrefix(s[n:], "\n") {
+		return n + 1
+	}
+	if isRegionalIndicator(r) {
+		if r2, n2 := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(r2) {
+			n += n2
+		}
+	}
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case isExtend(r):
+			n += size
+		case r == '‍': // ZWJ joins the next character
+			n += size
+			if n < len(s) {
+				_, size = utf8.DecodeRuneInString(s[n:])
+				n += size
+			}
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// isExtend reports whether r attaches to the character before it.
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= 0xFE00 && r <= 0xFE0F) || // variation selectors
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // emoji skin tones
+		(r >= 0xE0020 && r <= 0xE007F) // emoji tag sequences
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
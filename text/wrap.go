@@ -0,0 +1,93 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Wrap word-wraps s so lines are at most width runes where possible. Line
+// breaks in s are kept (blank lines separate paragraphs), runs of spaces
+// between words collapse to one, and a line's leading indentation is
+// repeated on its continuation lines. A word longer than width, such as a
+// URL, is put on a line of its own rather than broken.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	var b strings.Builder
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		wrapLine(&b, line, width)
+	}
+	return b.String()
+}
+
+func wrapLine(b *strings.Builder, line string, width int) {
+	rest := strings.TrimLeftFunc(line, unicode.IsSpace)
+	indent := line[:len(line)-len(rest)]
+
+	n := 0 // runes on the current output line
+	for _, word := range strings.Fields(rest) {
+		w := utf8.RuneCountInString(word)
+		switch {
+		case n == 0:
+			b.WriteString(indent)
+			n = utf8.RuneCountInString(indent)
+		case n+1+w > width:
+			b.WriteByte('\n')
+			b.WriteString(indent)
+			n = utf8.RuneCountInString(indent)
+		default:
+			b.WriteByte(' ')
+			n++
+		}
+		b.WriteString(word)
+		n += w
+	}
+}
+
+// Indent adds prefix to the start of every non-blank line of s.
+//
+//	text.Indent("usage:\n  run\n", "    ") // "    usage:\n      run\n"
+func Indent(s, prefix string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		if !Blank(line) {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, EmptyText)
+}
+
+// Dedent removes the leading whitespace common to all non-blank lines of
+// s, so indented raw string literals can be written in line with the code.
+// Lines with only whitespace become empty.
+func Dedent(s string) string {
+	lines := strings.SplitAfter(s, "\n")
+	var margin string
+	first := true
+	for _, line := range lines {
+		if Blank(line) {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			margin, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, margin) {
+			margin = margin[:len(margin)-1]
+		}
+	}
+	for i, line := range lines {
+		if Blank(line) {
+			lines[i] = strings.TrimLeft(line, " \t")
+		} else {
+			lines[i] = line[len(margin):]
+		}
+	}
+	return strings.Join(lines, EmptyText)
+}